@@ -0,0 +1,57 @@
+// Copyright (c) 2023 Red Hat, Inc.
+
+package v1alpha1
+
+// Annotations set by users on resources watched by the operator, tuning its behavior for those resources.
+const (
+	// FailoverPriorityAnnotation sets the failover priority of a DRPlacementControl, an integer defaulting to 0.
+	// DRPlacementControls with higher priorities are failed over first, lower priorities are held until the higher
+	// ones reach the release phase.
+	FailoverPriorityAnnotation = "rdrtrigger.redhat.com/failover-priority"
+
+	// AutoFailoverAnnotation opts a DRPlacementControl, or all the DRPlacementControls in a Namespace, in or out of
+	// automatic failover, either "true" or "false". The DRPlacementControl annotation takes precedence over the
+	// Namespace one.
+	AutoFailoverAnnotation = "rdrtrigger.redhat.com/auto-failover"
+
+	// FailoverApprovedAnnotation approves the failover of a DRPlacementControl held for approval, i.e. when its
+	// ManagedCluster is flapping, "true" approves. It is removed by the failover, approving it only once.
+	FailoverApprovedAnnotation = "rdrtrigger.redhat.com/failover-approved"
+
+	// AutoFailbackAnnotation opts a DRPlacementControl in for automatic failback, "true" relocates it back to its
+	// preferred cluster once the cluster recovered for the failback hold period.
+	AutoFailbackAnnotation = "rdrtrigger.redhat.com/auto-failback"
+
+	// BreakGlassReasonAnnotation forces the failover of a DRPlacementControl, or of all the DRPlacementControls of a
+	// ManagedCluster, bypassing the phase and condition rules, i.e. PeerReady, at the risk of data loss. The value is the
+	// required reason for forcing the failover. The DRPlacementControl annotations take precedence over the
+	// ManagedCluster ones.
+	BreakGlassReasonAnnotation = "rdrtrigger.redhat.com/break-glass-reason"
+
+	// BreakGlassUntilAnnotation is the required expiry of the break-glass, an RFC 3339 timestamp no later than the
+	// break-glass maximum duration from now.
+	BreakGlassUntilAnnotation = "rdrtrigger.redhat.com/break-glass-until"
+
+	// FailoverApprovalRequiredAnnotation marks a ManagedCluster as requiring approval for failing over, "true" holds its
+	// failovers in a FailoverPlan until the plan is approved.
+	FailoverApprovalRequiredAnnotation = "rdrtrigger.redhat.com/failover-approval-required"
+
+	// PausedAnnotation pauses the operator when set on the operator Namespace, "true" keeps evaluating the failover
+	// rules without patching any DRPlacementControl, until removed or set to "false".
+	PausedAnnotation = "rdrtrigger.redhat.com/paused"
+
+	// CircuitBreakerResetAnnotation closes an open circuit breaker when set on the operator Namespace, an RFC 3339
+	// timestamp. ManagedClusters unavailable since before the reset are no longer counted as part of a mass outage.
+	CircuitBreakerResetAnnotation = "rdrtrigger.redhat.com/circuit-breaker-reset"
+)
+
+// Annotations set by the operator on resources it acts on, users should not modify them.
+const (
+	// LastAutomatedActionAnnotation records the time of the last automated failover of a DRPlacementControl, an
+	// RFC 3339 timestamp. It is used for enforcing the failover cooldown.
+	LastAutomatedActionAnnotation = "rdrtrigger.redhat.com/last-automated-action"
+
+	// CircuitBreakerOpenedAnnotation records the time the circuit breaker opened on the operator Namespace, an RFC 3339
+	// timestamp. It keeps the breaker open across operator restarts and leader changes, until it closes.
+	CircuitBreakerOpenedAnnotation = "rdrtrigger.redhat.com/circuit-breaker-opened"
+)
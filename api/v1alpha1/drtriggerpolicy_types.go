@@ -0,0 +1,159 @@
+// Copyright (c) 2023 Red Hat, Inc.
+
+package v1alpha1
+
+import (
+	ramenv1alpha1 "github.com/ramendr/ramen/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DRTriggerPolicySpec defines the failover eligibility rules for the selected ManagedClusters and DRPlacementControls
+type DRTriggerPolicySpec struct {
+	// Enabled toggles automatic failover for the selected ManagedClusters and DRPlacementControls.
+	// +kubebuilder:default=true
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// DryRun evaluates the failover rules without patching the selected DRPlacementControls. Failovers that would
+	// have been initiated are logged, counted, and recorded as Events.
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// ClusterSelector selects the ManagedClusters this policy applies to. Omitting it selects all ManagedClusters.
+	// +optional
+	ClusterSelector *metav1.LabelSelector `json:"clusterSelector,omitempty"`
+
+	// DRPlacementControlSelector selects the DRPlacementControls this policy applies to. Omitting it selects all
+	// DRPlacementControls.
+	// +optional
+	DRPlacementControlSelector *metav1.LabelSelector `json:"drPlacementControlSelector,omitempty"`
+
+	// AllowedPhases lists the DRPlacementControl phases eligible for initiating a failover.
+	// +kubebuilder:default={Deploying,Deployed,Relocated}
+	// +optional
+	AllowedPhases []ramenv1alpha1.DRState `json:"allowedPhases,omitempty"`
+
+	// RequiredConditions lists the DRPlacementControl condition types required to be true for initiating a failover.
+	// +kubebuilder:default={PeerReady}
+	// +optional
+	RequiredConditions []string `json:"requiredConditions,omitempty"`
+
+	// UnavailableGracePeriod is the minimum duration a ManagedCluster is required to be unavailable before initiating a
+	// failover, measured from the last transition time of its Available condition.
+	// +optional
+	UnavailableGracePeriod *metav1.Duration `json:"unavailableGracePeriod,omitempty"`
+
+	// UnavailableLeaseMultiplier derives the grace period from the ManagedCluster's spec.leaseDurationSeconds when
+	// UnavailableGracePeriod is not set. i.e., 5 will wait for 5 missed lease renewals.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	UnavailableLeaseMultiplier *int32 `json:"unavailableLeaseMultiplier,omitempty"`
+
+	// RequireWholeClusterOutage holds failovers until the outage of the ManagedCluster is classified as an outage of
+	// the whole cluster, and not of its agent only, using the health of the cluster ManagedClusterAddOns.
+	// +optional
+	RequireWholeClusterOutage bool `json:"requireWholeClusterOutage,omitempty"`
+
+	// Flapping sets stricter failover rules for flapping ManagedClusters, i.e. clusters with unstable links whose
+	// Available condition transitioned too many times within the flap detection window.
+	// +optional
+	Flapping *FlappingRules `json:"flapping,omitempty"`
+
+	// InFlightStrategy is the strategy for DRPlacementControls in the middle of a relocate, Relocating, or Initiating
+	// while the application still runs on the previous cluster, when their source or destination ManagedCluster becomes
+	// unavailable.
+	// +kubebuilder:default=Wait
+	// +optional
+	InFlightStrategy InFlightStrategy `json:"inFlightStrategy,omitempty"`
+
+	// FailbackWindows restricts the automatic failback of the selected DRPlacementControls to maintenance windows.
+	// Omitting it allows failing back at any time.
+	// +optional
+	FailbackWindows []MaintenanceWindow `json:"failbackWindows,omitempty"`
+}
+
+// MaintenanceWindow is a weekly recurring time window
+type MaintenanceWindow struct {
+	// Days lists the days of the week the window starts on. Omitting it starts the window every day.
+	// +optional
+	Days []Weekday `json:"days,omitempty"`
+
+	// Start is the time of day the window starts at, in UTC, formatted as HH:MM.
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	Start string `json:"start"`
+
+	// Duration is the duration of the window, up to a week.
+	Duration metav1.Duration `json:"duration"`
+}
+
+// Weekday is a day of the week
+// +kubebuilder:validation:Enum=Sunday;Monday;Tuesday;Wednesday;Thursday;Friday;Saturday
+type Weekday string
+
+// InFlightStrategy is the strategy for in-flight DRPlacementControl operations with an unavailable ManagedCluster
+// +kubebuilder:validation:Enum=Wait;Failover;Escalate
+type InFlightStrategy string
+
+const (
+	// InFlightWait leaves the in-flight operation as is, waiting for the ManagedCluster to recover
+	InFlightWait InFlightStrategy = "Wait"
+	// InFlightFailover converts the in-flight operation to a failover toward the healthy ManagedCluster
+	InFlightFailover InFlightStrategy = "Failover"
+	// InFlightEscalate leaves the in-flight operation as is, escalating it for a decision by an administrator
+	InFlightEscalate InFlightStrategy = "Escalate"
+)
+
+// FlappingRules defines the failover rules applied on top of the policy rules for flapping ManagedClusters
+type FlappingRules struct {
+	// UnavailableGracePeriod is the minimum duration a flapping ManagedCluster is required to be unavailable before
+	// initiating a failover. The longer of it and the policy grace period is used.
+	// +kubebuilder:default="15m"
+	// +optional
+	UnavailableGracePeriod *metav1.Duration `json:"unavailableGracePeriod,omitempty"`
+
+	// RequireApproval holds the failover of DRPlacementControls of flapping ManagedClusters until they are approved
+	// using the failover-approved annotation.
+	// +optional
+	RequireApproval bool `json:"requireApproval,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster,shortName=drtp
+// +kubebuilder:printcolumn:name="Enabled",type=boolean,JSONPath=`.spec.enabled`
+// +kubebuilder:printcolumn:name="Dry Run",type=boolean,JSONPath=`.spec.dryRun`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// DRTriggerPolicy is the Schema for the drtriggerpolicies API. It replaces the built-in failover eligibility rules for
+// the ManagedClusters and DRPlacementControls it selects.
+type DRTriggerPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec DRTriggerPolicySpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// DRTriggerPolicyList contains a list of DRTriggerPolicy
+type DRTriggerPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DRTriggerPolicy `json:"items"`
+}
+
+// IsEnabled returns true unless the policy explicitly disables automatic failover
+func (p *DRTriggerPolicy) IsEnabled() bool {
+	return p.Spec.Enabled == nil || *p.Spec.Enabled
+}
+
+// GetInFlightStrategy returns the strategy for in-flight DRPlacementControl operations, waiting unless set
+func (p *DRTriggerPolicy) GetInFlightStrategy() InFlightStrategy {
+	if p.Spec.InFlightStrategy == "" {
+		return InFlightWait
+	}
+	return p.Spec.InFlightStrategy
+}
+
+func init() {
+	SchemeBuilder.Register(&DRTriggerPolicy{}, &DRTriggerPolicyList{})
+}
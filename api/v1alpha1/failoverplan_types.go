@@ -0,0 +1,164 @@
+// Copyright (c) 2023 Red Hat, Inc.
+
+package v1alpha1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// FailoverPlanSpec identifies the outage of a ManagedCluster requiring approval for failing over, and carries the
+// approval
+type FailoverPlanSpec struct {
+	// ManagedCluster is the name of the unavailable ManagedCluster the plan fails over.
+	ManagedCluster string `json:"managedCluster"`
+
+	// UnavailableSince is the last transition time of the ManagedCluster Available condition, identifying the outage
+	// the plan is for.
+	UnavailableSince metav1.Time `json:"unavailableSince"`
+
+	// Approval is set by an administrator for executing the plan, failing over the listed DRPlacementControls.
+	// +optional
+	Approval *FailoverPlanApproval `json:"approval,omitempty"`
+}
+
+// FailoverPlanApproval is the approval of a FailoverPlan, executing it until it expires
+type FailoverPlanApproval struct {
+	// ApprovedBy is the identity of the approver, the username of the user setting the approval. It is verified by the
+	// failoverplan-approval ValidatingAdmissionPolicy.
+	// +kubebuilder:validation:MinLength=1
+	ApprovedBy string `json:"approvedBy"`
+
+	// ExpiresAt is the time the approval expires, the plan is no longer executed after it. The operator caps it to its
+	// maximum approval duration, since it observed the approval.
+	ExpiresAt metav1.Time `json:"expiresAt"`
+}
+
+// FailoverPlanPhase is the approval phase of a FailoverPlan
+// +kubebuilder:validation:Enum=Pending;Approved;Expired;Closed
+type FailoverPlanPhase string
+
+const (
+	// FailoverPlanPending is a plan awaiting approval
+	FailoverPlanPending FailoverPlanPhase = "Pending"
+	// FailoverPlanApproved is a plan approved and being executed
+	FailoverPlanApproved FailoverPlanPhase = "Approved"
+	// FailoverPlanExpired is a plan whose approval expired
+	FailoverPlanExpired FailoverPlanPhase = "Expired"
+	// FailoverPlanClosed is a plan of a past outage, superseded by a new outage or ended by the cluster recovery, its
+	// approval is ignored
+	FailoverPlanClosed FailoverPlanPhase = "Closed"
+)
+
+// FailoverPlanEntryState is the gating state of a DRPlacementControl listed in a FailoverPlan
+// +kubebuilder:validation:Enum=AwaitingApproval;Gated;FailoverTriggered
+type FailoverPlanEntryState string
+
+const (
+	// FailoverPlanEntryAwaitingApproval is a DRPlacementControl failing over once the plan is approved
+	FailoverPlanEntryAwaitingApproval FailoverPlanEntryState = "AwaitingApproval"
+	// FailoverPlanEntryGated is a DRPlacementControl held by a failover rule, i.e. its peer is not ready
+	FailoverPlanEntryGated FailoverPlanEntryState = "Gated"
+	// FailoverPlanEntryFailoverTriggered is a DRPlacementControl failed over by the approved plan
+	FailoverPlanEntryFailoverTriggered FailoverPlanEntryState = "FailoverTriggered"
+)
+
+// FailoverPlanEntry is a DRPlacementControl the plan fails over
+type FailoverPlanEntry struct {
+	// DRPlacementControl is the name of the DRPlacementControl.
+	DRPlacementControl string `json:"drPlacementControl"`
+
+	// Namespace is the namespace of the DRPlacementControl.
+	Namespace string `json:"namespace"`
+
+	// TargetCluster is the name of the ManagedCluster the DRPlacementControl is failed over to, once resolved.
+	// +optional
+	TargetCluster string `json:"targetCluster,omitempty"`
+
+	// State is the gating state of the DRPlacementControl.
+	State FailoverPlanEntryState `json:"state"`
+
+	// Reason is the reason of the failover decision, i.e. FailoverSkippedPhase.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// Message is the reason a gated DRPlacementControl is held.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// FailoverPlanStatus lists the DRPlacementControls the plan fails over, as last evaluated by the operator
+type FailoverPlanStatus struct {
+	// Phase is the approval phase of the plan.
+	// +optional
+	Phase FailoverPlanPhase `json:"phase,omitempty"`
+
+	// Entries lists the DRPlacementControls of the ManagedCluster and their gating state.
+	// +optional
+	Entries []FailoverPlanEntry `json:"entries,omitempty"`
+
+	// ObservedGeneration is the generation of the plan last evaluated, a new generation carries a new approval.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// ApprovalExpiresAt is the time the approval expires, its expiresAt capped to the maximum approval duration of the
+	// operator.
+	// +optional
+	ApprovalExpiresAt *metav1.Time `json:"approvalExpiresAt,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,shortName=fp
+// +kubebuilder:printcolumn:name="Cluster",type=string,JSONPath=`.spec.managedCluster`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Approved By",type=string,JSONPath=`.spec.approval.approvedBy`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// FailoverPlan is the Schema for the failoverplans API. It is created by the operator for an unavailable ManagedCluster
+// requiring approval, listing the DRPlacementControls it would fail over. The plan is executed once approved.
+type FailoverPlan struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// +kubebuilder:validation:XValidation:rule="self.managedCluster == oldSelf.managedCluster && self.unavailableSince == oldSelf.unavailableSince",message="managedCluster and unavailableSince are immutable"
+	Spec   FailoverPlanSpec   `json:"spec,omitempty"`
+	Status FailoverPlanStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// FailoverPlanList contains a list of FailoverPlan
+type FailoverPlanList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []FailoverPlan `json:"items"`
+}
+
+// IsApproved returns true if the plan is not closed and carries an approval not expired at the given time, neither
+// its expiresAt nor its capped expiry
+func (p *FailoverPlan) IsApproved(now time.Time) bool {
+	if p.Status.ApprovalExpiresAt != nil && !now.Before(p.Status.ApprovalExpiresAt.Time) {
+		return false
+	}
+	return p.Status.Phase != FailoverPlanClosed && p.Spec.Approval != nil && p.Spec.Approval.ApprovedBy != "" && now.Before(p.Spec.Approval.ExpiresAt.Time)
+}
+
+// GetPhase returns the approval phase of the plan at the given time
+func (p *FailoverPlan) GetPhase(now time.Time) FailoverPlanPhase {
+	switch {
+	case p.Status.Phase == FailoverPlanClosed:
+		return FailoverPlanClosed
+	case p.IsApproved(now):
+		return FailoverPlanApproved
+	case p.Spec.Approval != nil:
+		return FailoverPlanExpired
+	default:
+		return FailoverPlanPending
+	}
+}
+
+func init() {
+	SchemeBuilder.Register(&FailoverPlan{}, &FailoverPlanList{})
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2023 Red Hat, Inc.
+
+package v1alpha1
+
+import (
+	ramenv1alpha1 "github.com/ramendr/ramen/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// FailoverRecordSpec captures why and by whom an automated failover was initiated, it is immutable once created
+type FailoverRecordSpec struct {
+	// DRPlacementControl is the name of the failed over DRPlacementControl, residing in the record's namespace.
+	DRPlacementControl string `json:"drPlacementControl"`
+
+	// ManagedCluster is the name of the unavailable ManagedCluster that triggered the failover.
+	ManagedCluster string `json:"managedCluster"`
+
+	// TargetCluster is the name of the ManagedCluster the DRPlacementControl is failed over to.
+	// +optional
+	TargetCluster string `json:"targetCluster,omitempty"`
+
+	// ManagedClusterConditions is a snapshot of the ManagedCluster conditions at the time of the failover.
+	// +optional
+	ManagedClusterConditions []metav1.Condition `json:"managedClusterConditions,omitempty"`
+
+	// PhaseBeforeFailover is the DRPlacementControl phase before it was patched for a failover.
+	// +optional
+	PhaseBeforeFailover ramenv1alpha1.DRState `json:"phaseBeforeFailover,omitempty"`
+
+	// PeerReady is the status of the DRPlacementControl PeerReady condition before it was patched for a failover.
+	PeerReady metav1.ConditionStatus `json:"peerReady"`
+
+	// Policy is the name of the DRTriggerPolicy the failover was evaluated with, or built-in.
+	Policy string `json:"policy"`
+
+	// BreakGlassReason is the reason given for a failover forced using the break-glass annotations, bypassing the
+	// phase and condition rules at the risk of data loss.
+	// +optional
+	BreakGlassReason string `json:"breakGlassReason,omitempty"`
+
+	// OperatorVersion is the version of the operator that initiated the failover.
+	OperatorVersion string `json:"operatorVersion"`
+
+	// Timestamp is the time the DRPlacementControl was patched for a failover.
+	Timestamp metav1.Time `json:"timestamp"`
+}
+
+// FailoverRecordStatus follows the failed over DRPlacementControl until the failover is completed
+type FailoverRecordStatus struct {
+	// Phase is the last observed DRPlacementControl phase, i.e. FailingOver and FailedOver.
+	// +optional
+	Phase ramenv1alpha1.DRState `json:"phase,omitempty"`
+
+	// CompletionTime is the time the DRPlacementControl was first observed as FailedOver.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=fr
+// +kubebuilder:printcolumn:name="DRPC",type=string,JSONPath=`.spec.drPlacementControl`
+// +kubebuilder:printcolumn:name="Cluster",type=string,JSONPath=`.spec.managedCluster`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// FailoverRecord is the Schema for the failoverrecords API. It is created by the operator for every DRPlacementControl
+// it patches for a failover, serving as an audit trail for automated failovers.
+type FailoverRecord struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="spec is immutable"
+	Spec   FailoverRecordSpec   `json:"spec,omitempty"`
+	Status FailoverRecordStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// FailoverRecordList contains a list of FailoverRecord
+type FailoverRecordList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []FailoverRecord `json:"items"`
+}
+
+// IsCompleted returns true once the failed over DRPlacementControl was observed as FailedOver
+func (r *FailoverRecord) IsCompleted() bool {
+	return r.Status.Phase == ramenv1alpha1.FailedOver
+}
+
+func init() {
+	SchemeBuilder.Register(&FailoverRecord{}, &FailoverRecordList{})
+}
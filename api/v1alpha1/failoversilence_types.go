@@ -0,0 +1,70 @@
+// Copyright (c) 2023 Red Hat, Inc.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// FailoverSilenceSpec defines the ManagedClusters and DRPlacementControls silenced, and when
+// +kubebuilder:validation:XValidation:rule="has(self.endTime) || (has(self.schedule) && has(self.duration))",message="either an endTime, or a schedule with a duration, is required"
+type FailoverSilenceSpec struct {
+	// ClusterSelector selects the ManagedClusters silenced. Omitting it selects all ManagedClusters.
+	// +optional
+	ClusterSelector *metav1.LabelSelector `json:"clusterSelector,omitempty"`
+
+	// DRPlacementControlSelector selects the DRPlacementControls silenced. Omitting it selects all DRPlacementControls.
+	// +optional
+	DRPlacementControlSelector *metav1.LabelSelector `json:"drPlacementControlSelector,omitempty"`
+
+	// StartTime is the time the silence starts at. Omitting it starts the silence right away.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// EndTime is the time the silence ends at. Omitting it, a schedule is required.
+	// +optional
+	EndTime *metav1.Time `json:"endTime,omitempty"`
+
+	// Schedule is a recurring cron schedule, in UTC, of the times the silence starts at, i.e. "0 2 * * 6" for every
+	// Saturday at 02:00, with days of week from 0 to 6 or SUN to SAT. The schedule is only active between the start and
+	// end times, if set.
+	// +optional
+	Schedule string `json:"schedule,omitempty"`
+
+	// Duration is the duration of every scheduled silence, up to a week.
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// Reason is the reason for silencing automatic failovers, i.e. a planned upgrade.
+	// +kubebuilder:validation:MinLength=1
+	Reason string `json:"reason"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster,shortName=fs
+// +kubebuilder:printcolumn:name="Reason",type=string,JSONPath=`.spec.reason`
+// +kubebuilder:printcolumn:name="Schedule",type=string,JSONPath=`.spec.schedule`
+// +kubebuilder:printcolumn:name="End",type=date,JSONPath=`.spec.endTime`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// FailoverSilence is the Schema for the failoversilences API. While active, automatic failovers are not initiated for
+// the ManagedClusters and DRPlacementControls it selects, i.e. during planned maintenance.
+type FailoverSilence struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec FailoverSilenceSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// FailoverSilenceList contains a list of FailoverSilence
+type FailoverSilenceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []FailoverSilence `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&FailoverSilence{}, &FailoverSilenceList{})
+}
@@ -0,0 +1,18 @@
+{{/*
+Copyright (c) 2023 Red Hat, Inc.
+*/}}
+
+{{/*
+Renders the operator flags value as manager arguments, list values are repeated for every item
+*/}}
+{{- define "rdrtrigger.flags" -}}
+{{- $args := list }}
+{{- range $flag, $value := .Values.operator.flags }}
+{{- range (kindIs "slice" $value | ternary $value (list $value)) }}
+{{- $args = append $args (printf "--%s=%v" $flag .) }}
+{{- end }}
+{{- end }}
+{{- if $args }}
+{{- toYaml $args }}
+{{- end }}
+{{- end }}
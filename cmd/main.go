@@ -5,7 +5,10 @@ package main
 import (
 	"github.com/spf13/cobra"
 	"k8s.io/component-base/cli"
+	"os"
+	"regional-dr-trigger-operator/internal/controller"
 	"regional-dr-trigger-operator/internal/operator"
+	"time"
 )
 
 // command used for running the operator
@@ -49,6 +52,138 @@ func init() {
 		false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers",
 	)
+	cmd.Flags().BoolVar(
+		&oper.Options.DryRun,
+		"dry-run",
+		false,
+		"If set, failover decisions are logged, counted, and recorded as events, without patching DRPlacementControls.")
+	cmd.Flags().IntVar(
+		&oper.Options.MaxFailovers,
+		"max-failovers",
+		0,
+		"The maximum number of failovers initiated within the failover limit window, failovers over it are queued. 0 is unlimited.")
+	cmd.Flags().IntVar(
+		&oper.Options.MaxFailoversPerCluster,
+		"max-failovers-per-cluster",
+		0,
+		"The maximum number of failovers initiated towards a single target cluster within the failover limit window, failovers over it are queued. 0 is unlimited.")
+	cmd.Flags().DurationVar(
+		&oper.Options.FailoverLimitWindow,
+		"failover-limit-window",
+		10*time.Minute,
+		"The sliding time window failovers are counted for the failover limits.")
+	cmd.Flags().StringVar(
+		&oper.Options.PriorityReleasePhase,
+		"priority-release-phase",
+		"FailedOver",
+		"The phase higher priority DRPlacementControls are required to reach before failing over lower priorities, FailingOver or FailedOver.")
+	cmd.Flags().BoolVar(
+		&oper.Options.OptInOnly,
+		"opt-in-only",
+		false,
+		"If set, only DRPlacementControls opted in for automatic failover, by them or by their Namespace, are failed over.")
+	cmd.Flags().DurationVar(
+		&oper.Options.RequeueInterval,
+		"requeue-interval",
+		5*time.Minute,
+		"The interval for re-evaluating the DRPlacementControls of a cluster while it stays unavailable. 0 disables it.")
+	cmd.Flags().Float64Var(
+		&oper.Options.BreakerThreshold,
+		"circuit-breaker-threshold",
+		0,
+		"The fraction of ManagedClusters, or of DRPolicy clusters, becoming unavailable within the circuit breaker window pausing automatic failovers, i.e. 0.5. 0 disables it.")
+	cmd.Flags().DurationVar(
+		&oper.Options.BreakerWindow,
+		"circuit-breaker-window",
+		5*time.Minute,
+		"The time window clusters becoming unavailable are counted for the circuit breaker.")
+	cmd.Flags().IntVar(
+		&oper.Options.FlapThreshold,
+		"flap-threshold",
+		0,
+		"The number of availability transitions within the flap window marking a ManagedCluster as flapping, applying stricter failover rules. 0 disables it.")
+	cmd.Flags().DurationVar(
+		&oper.Options.FlapWindow,
+		"flap-window",
+		time.Hour,
+		"The time window ManagedCluster availability transitions are counted for flap detection.")
+	cmd.Flags().DurationVar(
+		&oper.Options.FailoverCooldown,
+		"failover-cooldown",
+		0,
+		"The minimum duration after a failover or relocate before the same DRPlacementControl can be failed over again. 0 disables it.")
+	cmd.Flags().DurationVar(
+		&oper.Options.FailbackHoldPeriod,
+		"failback-hold-period",
+		time.Hour,
+		"The duration a recovered cluster is required to be available, and the DRPlacementControls opted in for automatic failback PeerReady, before relocating them back.")
+	cmd.Flags().DurationVar(
+		&oper.Options.BreakGlassMaxDuration,
+		"break-glass-max-duration",
+		4*time.Hour,
+		"The maximum duration a break-glass forcing failovers can be set for, break-glass expiring later is ignored.")
+	cmd.Flags().DurationVar(
+		&oper.Options.ApprovalMaxDuration,
+		"failover-approval-max-duration",
+		24*time.Hour,
+		"The maximum duration a FailoverPlan approval is executed for, since the operator observed it. 0 does not cap approvals.")
+	cmd.Flags().StringVar(
+		&oper.Options.LeaseDetection,
+		"lease-detection",
+		"",
+		"The use of the ManagedCluster lease staleness, early marks clusters with a stale lease as unavailable, confirm requires a stale lease for failing over. Empty disables it.")
+	cmd.Flags().StringVar(
+		&oper.Options.LeaseName,
+		"cluster-lease-name",
+		controller.DefaultClusterLeaseName,
+		"The name of the Lease renewed by the registration agent in the ManagedCluster namespace, used for lease detection.")
+	cmd.Flags().DurationVar(
+		&oper.Options.LeaseStaleAfter,
+		"lease-stale-after",
+		2*time.Minute,
+		"The duration since the last ManagedCluster, or ManagedClusterAddOn, lease renewal marking it as stale, used for lease detection and outage classification.")
+	cmd.Flags().StringSliceVar(
+		&oper.Options.OutageAddons,
+		"outage-addons",
+		controller.DefaultOutageAddons,
+		"The ManagedClusterAddOns inspected for classifying the outage of a cluster as agent only, or whole cluster.")
+	cmd.Flags().StringVar(
+		&oper.Options.PrometheusURL,
+		"prometheus-url",
+		"",
+		"The URL of a Prometheus compatible HTTP API, i.e. the ACM Observability Thanos, queried for confirming cluster outages. Empty disables it.")
+	cmd.Flags().StringArrayVar(
+		&oper.Options.PrometheusQueries,
+		"prometheus-query",
+		nil,
+		"A PromQL query returning a result only while the cluster is down, "+controller.ClusterPlaceholder+" is replaced with the cluster name. "+
+			"Can be repeated, all the queries must agree for confirming the outage.")
+	cmd.Flags().StringVar(
+		&oper.Options.PrometheusTokenFile,
+		"prometheus-bearer-token-file",
+		"",
+		"The path of a file holding a bearer token for authenticating the Prometheus queries.")
+	cmd.Flags().DurationVar(
+		&oper.Options.PrometheusInterval,
+		"prometheus-interval",
+		time.Minute,
+		"The interval for querying Prometheus again for failovers held by an unconfirmed outage.")
+	cmd.Flags().DurationVar(
+		&oper.Options.PrometheusTimeout,
+		"prometheus-timeout",
+		30*time.Second,
+		"The timeout of the Prometheus queries, a query timing out holds the failover like a failed query.")
+	cmd.Flags().StringVar(
+		&oper.Options.PrometheusCAFile,
+		"prometheus-ca-file",
+		"",
+		"The path of a PEM CA bundle for verifying the Prometheus server certificate, i.e. the service CA. Empty uses the system roots.")
+	cmd.Flags().StringVar(
+		&oper.Options.Namespace,
+		"namespace",
+		os.Getenv("POD_NAMESPACE"),
+		"The operator Namespace, annotated for pausing the operator and resetting the circuit breaker. "+
+			"Defaults to the POD_NAMESPACE environment variable.")
 
 	cmd.RunE = oper.Run
 }
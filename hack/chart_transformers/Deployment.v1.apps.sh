@@ -47,3 +47,7 @@ for container in $containers; do
     yq -i ".operator.$container_fixed.resources = $resources" "$temp_folder"/values.yaml
     yq -i "(.spec.template.spec.containers[] | select(.name == \"$container\") | .resources) = \"{{ .Values.operator.$container_fixed.resources | toYaml | nindent 12 }}\"" "$target_manifest"
 done
+
+# move the operator flags to the values, rendered as manager arguments by the flags template appended to the arguments
+yq -i '.operator.flags = {}' "$temp_folder"/values.yaml
+yq -i '(.spec.template.spec.containers[] | select(.name == "rdrtrigger") | .args) += ["{{- include \"rdrtrigger.flags\" . | nindent 12 }}"]' "$target_manifest"
@@ -90,8 +90,11 @@ for temp_template in "$temp_folder"/templates/*.yml; do
     # inject helm-related labels
     inject_helm_labels "$temp_template"
     # transformers use yq to inject templates to the template file, we surround our templates with quotes to
-    # suppress parsing by yq. these quotes need to be removed from the template file or they break templating
-    $bin_sed -i -e "s/'//g" "$temp_template"
+    # suppress parsing by yq. these quotes need to be removed from the template file or they break templating.
+    # only quotes surrounding templates are removed, crd descriptions include apostrophes
+    $bin_sed -i -e "s/'\({{.*}}\)'/\1/g" "$temp_template"
+    # list items holding a whitespace trimming template render their own items, their dash is removed as well
+    $bin_sed -i -e "s/- \({{-.*}}\)$/\1/" "$temp_template"
 done
 
 # prepare target folder (all current content will be deleted)
@@ -0,0 +1,105 @@
+// Copyright (c) 2023 Red Hat, Inc.
+
+package controller
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	rdrtriggerv1alpha1 "regional-dr-trigger-operator/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// DefaultOutageAddons are the ManagedClusterAddOns inspected for classifying outages when none are set
+var DefaultOutageAddons = []string{"work-manager", "application-manager"}
+
+// OutageClass is the classification of the outage of an unavailable ManagedCluster
+type OutageClass string
+
+const (
+	// OutageAgentOnly is an outage of the cluster agent only, the cluster addons are still alive
+	OutageAgentOnly OutageClass = "AgentOnly"
+	// OutageWholeCluster is an outage of the whole cluster, none of the cluster addons is alive
+	OutageWholeCluster OutageClass = "WholeCluster"
+	// OutageUnknown is an outage that can not be classified, none of the inspected addons is installed on the cluster
+	OutageUnknown OutageClass = "Unknown"
+)
+
+// OutageClassification classifies the outages of ManagedClusters using the health of their ManagedClusterAddOns. A
+// crashed agent and a real cluster outage both mark the cluster unavailable, but addons still alive on the cluster
+// keep renewing their leases.
+type OutageClassification struct {
+	// Addons are the names of the ManagedClusterAddOns inspected
+	Addons []string
+	// StaleAfter is the duration since the last addon lease renewal marking the addon as not alive
+	StaleAfter time.Duration
+}
+
+// addonNames returns the names of the ManagedClusterAddOns inspected, the default ones when none are set
+func (o OutageClassification) addonNames() []string {
+	if len(o.Addons) == 0 {
+		return DefaultOutageAddons
+	}
+	return o.Addons
+}
+
+// classifiesOutages returns true if any DRTriggerPolicy requires whole cluster outages, relying on the classification
+// of outages. Policies failing to list are assumed to require it.
+func (r *DRTriggerController) classifiesOutages(ctx context.Context) bool {
+	policies, err := r.listPolicies(ctx)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "failed listing policies")
+		return true
+	}
+	return slices.ContainsFunc(policies, func(policy rdrtriggerv1alpha1.DRTriggerPolicy) bool {
+		return policy.Spec.RequireWholeClusterOutage
+	})
+}
+
+// classify is used for classifying the outage of the ManagedCluster. An addon is alive if its lease in the
+// ManagedCluster namespace is renewed within the stale duration, or, for addons without a lease, if it is reported
+// available. Returns the duration after which the classification may change, once the freshest lease is stale.
+func (o OutageClassification) classify(ctx context.Context, c client.Client, mc *clusterv1.ManagedCluster) (OutageClass, time.Duration, error) {
+	addons := &addonv1alpha1.ManagedClusterAddOnList{}
+	if err := c.List(ctx, addons, client.InNamespace(mc.Name)); err != nil {
+		return OutageUnknown, 0, err
+	}
+
+	names := o.addonNames()
+	class := OutageUnknown
+	var recheck time.Duration
+	for _, addon := range addons.Items {
+		if !slices.Contains(names, addon.Name) {
+			continue
+		}
+
+		lease := &coordinationv1.Lease{}
+		err := c.Get(ctx, client.ObjectKey{Namespace: mc.Name, Name: addon.Name}, lease)
+		if client.IgnoreNotFound(err) != nil {
+			return OutageUnknown, 0, err
+		}
+
+		alive := false
+		if err == nil && lease.Spec.RenewTime != nil {
+			if staleIn := o.StaleAfter - time.Since(lease.Spec.RenewTime.Time); staleIn > 0 {
+				alive = true
+				recheck = minRequeue(recheck, staleIn)
+			}
+		} else {
+			alive = meta.IsStatusConditionTrue(addon.Status.Conditions, addonv1alpha1.ManagedClusterAddOnConditionAvailable)
+		}
+
+		if alive {
+			class = OutageAgentOnly
+		} else if class == OutageUnknown {
+			class = OutageWholeCluster
+		}
+	}
+	return class, recheck, nil
+}
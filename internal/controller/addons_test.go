@@ -0,0 +1,86 @@
+// Copyright (c) 2023 Red Hat, Inc.
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	ramenv1alpha1 "github.com/ramendr/ramen/api/v1alpha1"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	rdrtriggerv1alpha1 "regional-dr-trigger-operator/api/v1alpha1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"time"
+)
+
+var _ = Context("Outage Classification", func() {
+	It("should hold failovers of agent only outages for policies requiring whole cluster outages", func(ctx SpecContext) {
+		testName := "outage"
+		selected := map[string]string{"test": testName}
+
+		By("Create an unavailable ManagedCluster with an addon renewing its lease")
+		mc := createUnavailableCluster(ctx, testName)
+		mcNs := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: mc.Name}}
+		Expect(testClient.Create(ctx, mcNs)).To(Succeed())
+		addon := &addonv1alpha1.ManagedClusterAddOn{
+			ObjectMeta: metav1.ObjectMeta{Namespace: mc.Name, Name: "work-manager"},
+		}
+		Expect(testClient.Create(ctx, addon)).To(Succeed())
+		renewTime := metav1.NowMicro()
+		lease := &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{Namespace: mc.Name, Name: addon.Name},
+			Spec:       coordinationv1.LeaseSpec{RenewTime: &renewTime},
+		}
+		Expect(testClient.Create(ctx, lease)).To(Succeed())
+
+		By("Create a DRPlacementControl eligible for a failover")
+		drControl, ns := createDRControl(ctx, testName, mc.Name, selected, ramenv1alpha1.Deployed,
+			drCondition(ramenv1alpha1.ConditionPeerReady, metav1.ConditionTrue))
+
+		By("Create a DRTriggerPolicy requiring a whole cluster outage")
+		policy := &rdrtriggerv1alpha1.DRTriggerPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: testName},
+			Spec: rdrtriggerv1alpha1.DRTriggerPolicySpec{
+				DRPlacementControlSelector: &metav1.LabelSelector{MatchLabels: selected},
+				RequireWholeClusterOutage:  true,
+			},
+		}
+		Expect(testClient.Create(ctx, policy)).To(Succeed())
+
+		By("Reconcile for the MC")
+		recorder := record.NewFakeRecorder(10)
+		outageController := &DRTriggerController{Client: testClient, Scheme: drtController.Scheme, Recorder: recorder,
+			Outages: OutageClassification{Addons: []string{addon.Name}, StaleAfter: 2 * time.Minute}}
+		res, err := outageController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Verify the DRPC was not failed-over and requeued for the addon lease to go stale")
+		Expect(drAction(ctx, drControl)()).To(Equal(ramenv1alpha1.ActionRelocate))
+		Expect(recordedEvents(recorder)).To(ContainElement(ContainSubstring(string(OutageAgentOnly))))
+		Expect(res.RequeueAfter).To(BeNumerically("~", 2*time.Minute, 5*time.Second))
+
+		By("Make the addon lease stale and reconcile for the MC")
+		Expect(testClient.Get(ctx, client.ObjectKeyFromObject(lease), lease)).To(Succeed())
+		staleTime := metav1.NewMicroTime(time.Now().Add(-10 * time.Minute))
+		lease.Spec.RenewTime = &staleTime
+		Expect(testClient.Update(ctx, lease)).To(Succeed())
+		_, err = outageController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Verify the DRPC was failed-over")
+		Expect(drAction(ctx, drControl)()).To(Equal(ramenv1alpha1.ActionFailover))
+
+		By("Cleanups")
+		Expect(testClient.Delete(ctx, policy)).To(Succeed())
+		Expect(testClient.Delete(ctx, drControl)).To(Succeed())
+		Expect(testClient.Delete(ctx, ns)).To(Succeed())
+		Expect(testClient.Delete(ctx, lease)).To(Succeed())
+		Expect(testClient.Delete(ctx, addon)).To(Succeed())
+		Expect(testClient.Delete(ctx, mcNs)).To(Succeed())
+		Expect(testClient.Delete(ctx, mc)).To(Succeed())
+	})
+})
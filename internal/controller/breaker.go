@@ -0,0 +1,265 @@
+// Copyright (c) 2023 Red Hat, Inc.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	ramenv1alpha1 "github.com/ramendr/ramen/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	rdrtriggerv1alpha1 "regional-dr-trigger-operator/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// breakerRequeueInterval is the interval for re-evaluating an unavailable cluster while the circuit breaker is open
+const breakerRequeueInterval = 30 * time.Second
+
+var circuitBreakerOpenMetric = prometheus.NewGauge(prometheus.GaugeOpts{
+	Name: "dr_circuit_breaker_open",
+	Help: "Gauge set to 1 while the Regional DR Trigger Operator circuit breaker is open, pausing automatic failovers",
+})
+
+var circuitBreakerTripMetric = prometheus.NewCounter(prometheus.CounterOpts{
+	Name: "dr_circuit_breaker_trip_count",
+	Help: "Counter for the Regional DR Trigger Operator circuit breaker opening on a mass outage",
+})
+
+// CircuitBreaker pauses automatic failovers on a mass outage, i.e. a hub network partition marking the whole fleet
+// unavailable. It opens when more than the threshold fraction of the ManagedClusters, or of the clusters in DRPolicies,
+// became unavailable within the window. It closes once the unavailable clusters recover below the threshold, or when
+// reset using the CircuitBreakerResetAnnotation on the operator Namespace. The open state is saved on the operator
+// Namespace, so it survives operator restarts and leader changes, the Namespace is required. A nil breaker is always
+// closed.
+type CircuitBreaker struct {
+	// Threshold is the fraction of unavailable clusters opening the breaker, 0 disables the breaker
+	Threshold float64
+	// Window is the duration clusters are counted for after becoming unavailable
+	Window time.Duration
+	// Namespace is the operator Namespace, saving the open state and checked for the reset annotation
+	Namespace string
+
+	mu       sync.Mutex
+	open     bool
+	openedAt time.Time
+	reason   string
+	// closed is the opening time of the last closed breaker, its saved state may still be read from the cache
+	closed time.Time
+}
+
+// breakerStatus is the outcome of a circuit breaker evaluation
+type breakerStatus struct {
+	open    bool
+	changed bool
+	reason  string
+}
+
+// clusterCount counts the unavailable clusters out of a set of clusters
+type clusterCount struct {
+	total       int
+	unavailable int
+	recent      int
+}
+
+// add is used for counting a cluster
+func (c *clusterCount) add(unavailable, recent bool) {
+	c.total++
+	if unavailable {
+		c.unavailable++
+	}
+	if recent {
+		c.recent++
+	}
+}
+
+// exceeds returns true if the unavailable clusters, or only the recently unavailable ones, are over the threshold
+func (c clusterCount) exceeds(threshold float64, recentOnly bool) bool {
+	if c.total == 0 {
+		return false
+	}
+	count := c.unavailable
+	if recentOnly {
+		count = c.recent
+	}
+	return float64(count)/float64(c.total) > threshold
+}
+
+// evaluate is used for opening or closing the breaker based on the current ManagedClusters and DRPolicies
+func (b *CircuitBreaker) evaluate(ctx context.Context, c client.Client) (breakerStatus, error) {
+	if b == nil || b.Threshold <= 0 {
+		return breakerStatus{}, nil
+	}
+
+	ns, err := b.namespace(ctx, c)
+	if err != nil {
+		return breakerStatus{}, err
+	}
+	resetAt := b.resetAt(ctx, ns)
+
+	mcs := &clusterv1.ManagedClusterList{}
+	if err := c.List(ctx, mcs); err != nil {
+		return breakerStatus{}, err
+	}
+	drPolicies := &ramenv1alpha1.DRPolicyList{}
+	if err := c.List(ctx, drPolicies); err != nil {
+		return breakerStatus{}, err
+	}
+
+	drClusters := map[string]bool{}
+	for _, drPolicy := range drPolicies.Items {
+		for _, cluster := range drPolicy.Spec.DRClusters {
+			drClusters[cluster] = true
+		}
+	}
+
+	// clusters unavailable since before a reset are not part of the current outage
+	since := time.Now().Add(-b.Window)
+	if resetAt.After(since) {
+		since = resetAt
+	}
+
+	var fleet, drSet clusterCount
+	for _, mc := range mcs.Items {
+		if !meta.IsStatusConditionTrue(mc.Status.Conditions, clusterv1.ManagedClusterConditionJoined) ||
+			!mc.Spec.HubAcceptsClient {
+			continue
+		}
+		available := meta.FindStatusCondition(mc.Status.Conditions, clusterv1.ManagedClusterConditionAvailable)
+		unavailable := available == nil || available.Status != metav1.ConditionTrue
+		recent := unavailable && available != nil && available.LastTransitionTime.Time.After(since)
+		fleet.add(unavailable, recent)
+		if drClusters[mc.Name] {
+			drSet.add(unavailable, recent)
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// an open state saved before a restart, or by the previous leader
+	openedAt := annotatedTime(ctx, ns, rdrtriggerv1alpha1.CircuitBreakerOpenedAnnotation)
+	if !b.open && !openedAt.IsZero() && !openedAt.Equal(b.closed) {
+		b.open, b.openedAt = true, openedAt
+		b.reason = fmt.Sprintf("opened at %s", openedAt.Format(time.RFC3339))
+		circuitBreakerOpenMetric.Set(1)
+	}
+
+	if !b.open {
+		if !fleet.exceeds(b.Threshold, true) && !drSet.exceeds(b.Threshold, true) {
+			return breakerStatus{}, nil
+		}
+		openedAt := time.Now()
+		if err := b.save(ctx, c, ns, openedAt.UTC().Format(time.RFC3339)); err != nil {
+			return breakerStatus{}, err
+		}
+		b.open, b.openedAt = true, openedAt
+		b.reason = fmt.Sprintf(
+			"%d of %d managed clusters, and %d of %d dr policy clusters, became unavailable within %s",
+			fleet.recent, fleet.total, drSet.recent, drSet.total, b.Window)
+		circuitBreakerOpenMetric.Set(1)
+		circuitBreakerTripMetric.Inc()
+		return breakerStatus{open: true, changed: true, reason: b.reason}, nil
+	}
+
+	if !resetAt.Before(b.openedAt.Truncate(time.Second)) {
+		return b.close(ctx, c, ns, fmt.Sprintf("reset at %s", resetAt.Format(time.RFC3339)))
+	}
+	if !fleet.exceeds(b.Threshold, false) && !drSet.exceeds(b.Threshold, false) {
+		return b.close(ctx, c, ns, fmt.Sprintf(
+			"%d of %d managed clusters, and %d of %d dr policy clusters, are unavailable",
+			fleet.unavailable, fleet.total, drSet.unavailable, drSet.total))
+	}
+	return breakerStatus{open: true, reason: b.reason}, nil
+}
+
+// close is used for closing the breaker, removing the saved open state, the caller holds the lock
+func (b *CircuitBreaker) close(ctx context.Context, c client.Client, ns *corev1.Namespace, reason string) (breakerStatus, error) {
+	if err := b.save(ctx, c, ns, ""); err != nil {
+		return breakerStatus{open: true, reason: b.reason}, err
+	}
+	b.closed = b.openedAt.Truncate(time.Second)
+	b.open, b.openedAt, b.reason = false, time.Time{}, ""
+	circuitBreakerOpenMetric.Set(0)
+	return breakerStatus{changed: true, reason: reason}, nil
+}
+
+// save is used for saving the time the breaker opened on the operator Namespace, empty removes it
+func (b *CircuitBreaker) save(ctx context.Context, c client.Client, ns *corev1.Namespace, openedAt string) error {
+	if ns.Annotations[rdrtriggerv1alpha1.CircuitBreakerOpenedAnnotation] == openedAt {
+		return nil
+	}
+
+	patched := ns.DeepCopy()
+	if openedAt == "" {
+		delete(patched.Annotations, rdrtriggerv1alpha1.CircuitBreakerOpenedAnnotation)
+	} else {
+		if patched.Annotations == nil {
+			patched.Annotations = map[string]string{}
+		}
+		patched.Annotations[rdrtriggerv1alpha1.CircuitBreakerOpenedAnnotation] = openedAt
+	}
+	return c.Patch(ctx, patched, client.MergeFrom(ns))
+}
+
+// namespace returns the operator Namespace, saving the breaker state
+func (b *CircuitBreaker) namespace(ctx context.Context, c client.Client) (*corev1.Namespace, error) {
+	ns := &corev1.Namespace{}
+	if err := c.Get(ctx, client.ObjectKey{Name: b.Namespace}, ns); err != nil {
+		return nil, err
+	}
+	return ns, nil
+}
+
+// Check is a healthz.Checker failing while the breaker is open, so a paused operator is reported as not ready. The
+// error names the open state, its reason, and when it opened.
+func (b *CircuitBreaker) Check(_ *http.Request) error {
+	if b == nil {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.open {
+		return nil
+	}
+	return fmt.Errorf("circuit breaker open since %s, automatic failovers are paused, %s",
+		b.openedAt.UTC().Format(time.RFC3339), b.reason)
+}
+
+// resetAt returns the time set by the reset annotation on the operator Namespace, or the zero time if not set
+func (b *CircuitBreaker) resetAt(ctx context.Context, ns *corev1.Namespace) time.Time {
+	return annotatedTime(ctx, ns, rdrtriggerv1alpha1.CircuitBreakerResetAnnotation)
+}
+
+// annotatedTime is a utility function returning the RFC 3339 time set by the annotation on the Namespace, or the zero
+// time if not set or malformed
+func annotatedTime(ctx context.Context, ns *corev1.Namespace, annotation string) time.Time {
+	if ns == nil {
+		return time.Time{}
+	}
+
+	value, found := ns.Annotations[annotation]
+	if !found {
+		return time.Time{}
+	}
+	at, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		log.FromContext(ctx).Info("ignoring malformed annotation", "annotation", annotation, "value", value)
+		return time.Time{}
+	}
+	return at
+}
+
+func init() {
+	metrics.Registry.MustRegister(circuitBreakerOpenMetric, circuitBreakerTripMetric)
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2023 Red Hat, Inc.
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	ramenv1alpha1 "github.com/ramendr/ramen/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	rdrtriggerv1alpha1 "regional-dr-trigger-operator/api/v1alpha1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"time"
+)
+
+var _ = Context("Circuit Breaker", func() {
+	It("should pause failovers on a mass outage until reset", func(ctx SpecContext) {
+		testName := "circuit-breaker"
+
+		By("Create the operator Namespace")
+		operatorNs := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: testName + "-operator"}}
+		Expect(testClient.Create(ctx, operatorNs)).To(Succeed())
+
+		By("Create an unavailable ManagedCluster")
+		mc := createUnavailableCluster(ctx, testName)
+
+		By("Create a DRPlacementControl eligible for a failover")
+		drControl, ns := createDRControl(ctx, testName, mc.Name, nil, ramenv1alpha1.Deployed,
+			drCondition(ramenv1alpha1.ConditionPeerReady, metav1.ConditionTrue))
+
+		By("Reconcile for the MC with a breaker opening for half of the dr policy clusters")
+		recorder := record.NewFakeRecorder(10)
+		breaker := &CircuitBreaker{Threshold: 0.4, Window: time.Minute, Namespace: operatorNs.Name}
+		breakerController := &DRTriggerController{Client: testClient, Scheme: drtController.Scheme, Recorder: recorder,
+			Breaker: breaker}
+		res, err := breakerController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Verify the breaker is open and the DRPC was not failed-over")
+		Expect(drAction(ctx, drControl)()).To(Equal(ramenv1alpha1.ActionRelocate))
+		Expect(recordedEvents(recorder)).To(ContainElement(ContainSubstring(ReasonCircuitBreakerOpen)))
+		Expect(res.RequeueAfter).To(Equal(breakerRequeueInterval))
+		Expect(testClient.Get(ctx, client.ObjectKeyFromObject(operatorNs), operatorNs)).To(Succeed())
+		Expect(operatorNs.Annotations).To(HaveKey(rdrtriggerv1alpha1.CircuitBreakerOpenedAnnotation))
+
+		By("Verify the ready check fails naming the open breaker, its reason, and when it opened")
+		Expect(breaker.Check(nil)).To(MatchError(SatisfyAll(
+			ContainSubstring("circuit breaker open since "+breaker.openedAt.UTC().Format(time.RFC3339)),
+			ContainSubstring("became unavailable within 1m0s"))))
+
+		By("Restart with a new breaker and reconcile for the MC")
+		breaker = &CircuitBreaker{Threshold: 0.4, Window: time.Minute, Namespace: operatorNs.Name}
+		breakerController.Breaker = breaker
+		_, err = breakerController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Verify the breaker is still open and the DRPC was not failed-over")
+		Expect(drAction(ctx, drControl)()).To(Equal(ramenv1alpha1.ActionRelocate))
+		Expect(breaker.open).To(BeTrue())
+
+		By("Reset the breaker using the operator Namespace annotation")
+		operatorNs.Annotations[rdrtriggerv1alpha1.CircuitBreakerResetAnnotation] = time.Now().UTC().Format(time.RFC3339)
+		Expect(testClient.Update(ctx, operatorNs)).To(Succeed())
+
+		By("Reconcile for the MC")
+		_, err = breakerController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Verify the breaker is closed and the DRPC was failed-over")
+		Expect(breaker.open).To(BeFalse())
+		Expect(breaker.Check(nil)).To(Succeed())
+		Expect(testClient.Get(ctx, client.ObjectKeyFromObject(operatorNs), operatorNs)).To(Succeed())
+		Expect(operatorNs.Annotations).NotTo(HaveKey(rdrtriggerv1alpha1.CircuitBreakerOpenedAnnotation))
+		Expect(recordedEvents(recorder)).To(ContainElement(ContainSubstring(ReasonCircuitBreakerClosed)))
+		Expect(drAction(ctx, drControl)()).To(Equal(ramenv1alpha1.ActionFailover))
+
+		By("Cleanups")
+		Expect(testClient.Delete(ctx, drControl)).To(Succeed())
+		Expect(testClient.Delete(ctx, ns)).To(Succeed())
+		Expect(testClient.Delete(ctx, mc)).To(Succeed())
+		Expect(testClient.Delete(ctx, operatorNs)).To(Succeed())
+	})
+})
@@ -0,0 +1,82 @@
+// Copyright (c) 2023 Red Hat, Inc.
+
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	ramenv1alpha1 "github.com/ramendr/ramen/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	rdrtriggerv1alpha1 "regional-dr-trigger-operator/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var drApplicationBreakGlassFailoverMetric = *prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "dr_application_break_glass_failover_count",
+	Help: "Counter for DR Applications failover forced using break-glass by the Regional DR Trigger Operator, risking data loss",
+}, []string{"dr_cluster_name", "dr_control_name", "dr_application_name"})
+
+// breakGlass is a failover forced using the break-glass annotations
+type breakGlass struct {
+	reason string
+	until  time.Time
+}
+
+// breakGlassFor is used for getting the active break-glass of a DRPlacementControl, set on it or on its ManagedCluster.
+// Returns nil if there is none, with the reason a requested break-glass is not active, i.e. it expired.
+func (r *DRTriggerController) breakGlassFor(mc *clusterv1.ManagedCluster, control ramenv1alpha1.DRPlacementControl) (*breakGlass, string) {
+	var obj client.Object
+	switch {
+	case requestsBreakGlass(&control):
+		obj = &control
+	case requestsBreakGlass(mc):
+		obj = mc
+	default:
+		return nil, ""
+	}
+
+	annotations := obj.GetAnnotations()
+	reason := annotations[rdrtriggerv1alpha1.BreakGlassReasonAnnotation]
+	if reason == "" {
+		return nil, fmt.Sprintf("%s annotation is required", rdrtriggerv1alpha1.BreakGlassReasonAnnotation)
+	}
+
+	until, err := time.Parse(time.RFC3339, annotations[rdrtriggerv1alpha1.BreakGlassUntilAnnotation])
+	if err != nil {
+		return nil, fmt.Sprintf("%s annotation is required as an RFC 3339 timestamp",
+			rdrtriggerv1alpha1.BreakGlassUntilAnnotation)
+	}
+
+	now := time.Now()
+	if !now.Before(until) {
+		return nil, fmt.Sprintf("expired at %s", until.Format(time.RFC3339))
+	}
+	if until.Sub(now) > r.BreakGlassMaxDuration {
+		return nil, fmt.Sprintf("expiry at %s exceeds the maximum duration of %s", until.Format(time.RFC3339),
+			r.BreakGlassMaxDuration)
+	}
+	return &breakGlass{reason: reason, until: until}, ""
+}
+
+// recordBreakGlassIgnored is used for recording why a break-glass requested for a DRPlacementControl was ignored
+func (r *DRTriggerController) recordBreakGlassIgnored(mc *clusterv1.ManagedCluster, control *ramenv1alpha1.DRPlacementControl, reason string) {
+	if reason != "" {
+		r.recordEvent(mc, control, corev1.EventTypeWarning, ReasonBreakGlassIgnored, "Break-glass ignored, %s", reason)
+	}
+}
+
+// requestsBreakGlass is a utility function returning true if the object has any of the break-glass annotations
+func requestsBreakGlass(obj client.Object) bool {
+	annotations := obj.GetAnnotations()
+	_, reason := annotations[rdrtriggerv1alpha1.BreakGlassReasonAnnotation]
+	_, until := annotations[rdrtriggerv1alpha1.BreakGlassUntilAnnotation]
+	return reason || until
+}
+
+func init() {
+	metrics.Registry.MustRegister(drApplicationBreakGlassFailoverMetric)
+}
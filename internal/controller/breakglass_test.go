@@ -0,0 +1,88 @@
+// Copyright (c) 2023 Red Hat, Inc.
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	ramenv1alpha1 "github.com/ramendr/ramen/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	rdrtriggerv1alpha1 "regional-dr-trigger-operator/api/v1alpha1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"time"
+)
+
+var _ = Context("Break-Glass", func() {
+	It("should failover dr controls with an active break-glass ignoring an unmet peer ready", func(ctx SpecContext) {
+		testName := "break-glass"
+
+		By("Create an unavailable ManagedCluster")
+		mc := createUnavailableCluster(ctx, testName)
+
+		By("Create a DRPlacementControl with peer not ready and a break-glass")
+		drControl, ns := createDRControl(ctx, testName, mc.Name, nil, ramenv1alpha1.Deployed,
+			drCondition(ramenv1alpha1.ConditionPeerReady, metav1.ConditionFalse))
+		drControl.Annotations = map[string]string{
+			rdrtriggerv1alpha1.BreakGlassReasonAnnotation: "site lost in a fire",
+			rdrtriggerv1alpha1.BreakGlassUntilAnnotation:  time.Now().Add(30 * time.Minute).UTC().Format(time.RFC3339),
+		}
+		Expect(testClient.Update(ctx, drControl)).To(Succeed())
+
+		By("Reconcile for the MC")
+		recorder := record.NewFakeRecorder(10)
+		glassController := &DRTriggerController{Client: testClient, Scheme: drtController.Scheme, Recorder: recorder,
+			BreakGlassMaxDuration: time.Hour}
+		_, err := glassController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Verify the DRPC was failed-over using break-glass")
+		Expect(drAction(ctx, drControl)()).To(Equal(ramenv1alpha1.ActionFailover))
+		Expect(recordedEvents(recorder)).To(ContainElement(ContainSubstring(ReasonBreakGlassFailover)))
+
+		By("Verify the FailoverRecord was created with the break-glass reason")
+		records := &rdrtriggerv1alpha1.FailoverRecordList{}
+		Expect(testClient.List(ctx, records, client.InNamespace(ns.Name))).To(Succeed())
+		Expect(records.Items).To(HaveLen(1))
+		Expect(records.Items[0].Spec.BreakGlassReason).To(Equal("site lost in a fire"))
+
+		By("Cleanups")
+		Expect(testClient.Delete(ctx, &records.Items[0])).To(Succeed())
+		Expect(testClient.Delete(ctx, drControl)).To(Succeed())
+		Expect(testClient.Delete(ctx, ns)).To(Succeed())
+		Expect(testClient.Delete(ctx, mc)).To(Succeed())
+	})
+
+	It("should ignore an expired break-glass", func(ctx SpecContext) {
+		testName := "break-glass-expired"
+
+		By("Create an unavailable ManagedCluster with an expired break-glass")
+		mc := createUnavailableCluster(ctx, testName)
+		mc.Annotations = map[string]string{
+			rdrtriggerv1alpha1.BreakGlassReasonAnnotation: "site lost in a flood",
+			rdrtriggerv1alpha1.BreakGlassUntilAnnotation:  time.Now().Add(-time.Minute).UTC().Format(time.RFC3339),
+		}
+		Expect(testClient.Update(ctx, mc)).To(Succeed())
+
+		By("Create a DRPlacementControl with peer not ready")
+		drControl, ns := createDRControl(ctx, testName, mc.Name, nil, ramenv1alpha1.Deployed,
+			drCondition(ramenv1alpha1.ConditionPeerReady, metav1.ConditionFalse))
+
+		By("Reconcile for the MC")
+		recorder := record.NewFakeRecorder(10)
+		glassController := &DRTriggerController{Client: testClient, Scheme: drtController.Scheme, Recorder: recorder,
+			BreakGlassMaxDuration: time.Hour}
+		_, err := glassController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Verify the DRPC was not failed-over and the break-glass was ignored")
+		Expect(drAction(ctx, drControl)()).To(Equal(ramenv1alpha1.ActionRelocate))
+		Expect(recordedEvents(recorder)).To(ContainElement(ContainSubstring(ReasonBreakGlassIgnored)))
+
+		By("Cleanups")
+		Expect(testClient.Delete(ctx, drControl)).To(Succeed())
+		Expect(testClient.Delete(ctx, ns)).To(Succeed())
+		Expect(testClient.Delete(ctx, mc)).To(Succeed())
+	})
+})
@@ -7,59 +7,135 @@ import (
 	"encoding/json"
 	"github.com/prometheus/client_golang/prometheus"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"strings"
+	"time"
 
 	"github.com/hashicorp/go-multierror"
 	ramenv1alpha1 "github.com/ramendr/ramen/api/v1alpha1"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	rdrtriggerv1alpha1 "regional-dr-trigger-operator/api/v1alpha1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
-// okToFailoverStates is a fixed array listing the state a DRPlacementControl needs to be in for us to initiate a failover.
-var okToFailoverStates = [...]ramenv1alpha1.DRState{ramenv1alpha1.Deploying, ramenv1alpha1.Deployed, ramenv1alpha1.Relocated}
-
 var drApplicationFailoverMetric = *prometheus.NewCounterVec(prometheus.CounterOpts{
 	Name: "dr_application_failover_count",
 	Help: "Counter for DR Applications failover initiated by the Regional DR Trigger Operator",
 }, []string{"dr_cluster_name", "dr_control_name", "dr_application_name"})
 
+var drApplicationFailoverDryRunMetric = *prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "dr_application_failover_dryrun_count",
+	Help: "Counter for DR Applications failover the Regional DR Trigger Operator would have initiated if not in dry-run mode",
+}, []string{"dr_cluster_name", "dr_control_name", "dr_application_name"})
+
+var drApplicationFailoverQueuedMetric = *prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "dr_application_failover_queued_count",
+	Help: "Counter for DR Applications failover queued by the Regional DR Trigger Operator for exceeding the failover limits",
+}, []string{"dr_cluster_name", "dr_control_name", "dr_application_name"})
+
+var drApplicationFailoverTargetUnavailableMetric = *prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "dr_application_failover_target_unavailable_count",
+	Help: "Counter for DR Applications failover refused by the Regional DR Trigger Operator for an unhealthy failover cluster",
+}, []string{"dr_cluster_name", "dr_control_name", "dr_application_name", "dr_failover_cluster_name"})
+
 // DRTriggerController is a receiver representing the DRTriggerOperator controller for ManagedCluster CRs
 type DRTriggerController struct {
-	Client client.Client
-	Scheme *runtime.Scheme
+	Client   client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	// DryRun evaluates the failover rules for all DRPlacementControls without patching them
+	DryRun bool
+	// Version is the operator version, recorded in FailoverRecords
+	Version string
+	// Limits caps the failovers initiated within a time window, failovers over the caps are queued
+	Limits FailoverLimits
+	// PriorityReleasePhase is the phase higher priority DRPlacementControls are required to reach before failing over
+	// the lower priority ones, i.e. FailingOver or FailedOver
+	PriorityReleasePhase ramenv1alpha1.DRState
+	// OptInOnly only considers DRPlacementControls opted in for automatic failover, by them or by their Namespace
+	OptInOnly bool
+	// RequeueInterval is the interval for re-evaluating the DRPlacementControls of a cluster while it stays
+	// unavailable, 0 disables the periodic re-evaluation
+	RequeueInterval time.Duration
+	// Breaker pauses automatic failovers on a mass outage, nil disables it
+	Breaker *CircuitBreaker
+	// Flapping marks clusters oscillating between available and unavailable as flapping, applying stricter rules
+	Flapping FlapDetection
+	// Cooldown is the minimum duration between actions on a DRPlacementControl before failing it over again, 0
+	// disables the cooldown
+	Cooldown time.Duration
+	// FailbackHoldPeriod is the duration a recovered cluster is required to be available before relocating back the
+	// DRPlacementControls opted in for automatic failback
+	FailbackHoldPeriod time.Duration
+	// BreakGlassMaxDuration is the maximum duration a break-glass can be set for before it expires
+	BreakGlassMaxDuration time.Duration
+	// ApprovalMaxDuration is the maximum duration a FailoverPlan approval is executed for, zero does not cap approvals
+	ApprovalMaxDuration time.Duration
+	// Namespace is the operator Namespace, annotated for pausing the operator, empty disables pausing
+	Namespace string
+	// LeaseDetection uses the staleness of the ManagedCluster lease as an earlier, or a confirming, signal of an outage
+	LeaseDetection LeaseDetection
+	// Outages classifies the outages of clusters as agent only or whole cluster, using the health of their addons
+	Outages OutageClassification
+	// Prometheus confirms the outages of clusters using PromQL queries, i.e. against ACM Observability
+	Prometheus PrometheusSignal
+
+	flaps flapTracker
 }
 
 // SetupWithManager is used for setting up the controller. Using Predicates for filtering, only accepting
-// ManagedCluster eligible for failing over
+// ManagedCluster eligible for failing over. Events of the resources the failover rules depend on are mapped to the
+// ManagedClusters they affect.
 func (r *DRTriggerController) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		Named("regional-dr-trigger-controller").
-		For(&clusterv1.ManagedCluster{}).
-		WithEventFilter(predicate.Funcs{
+		For(&clusterv1.ManagedCluster{}, builder.WithPredicates(predicate.Funcs{
 			DeleteFunc: func(e event.DeleteEvent) bool {
 				return false
 			},
-		}).
+		})).
+		Watches(&rdrtriggerv1alpha1.DRTriggerPolicy{}, handler.EnqueueRequestsFromMapFunc(r.mapPolicyToClusters)).
+		Watches(&ramenv1alpha1.DRPlacementControl{}, handler.EnqueueRequestsFromMapFunc(mapDRControlToCluster)).
+		Watches(&rdrtriggerv1alpha1.FailoverPlan{}, handler.EnqueueRequestsFromMapFunc(mapPlanToCluster)).
+		Watches(&rdrtriggerv1alpha1.FailoverSilence{}, handler.EnqueueRequestsFromMapFunc(r.mapSilenceToClusters)).
+		Watches(&corev1.Namespace{}, handler.EnqueueRequestsFromMapFunc(r.mapNamespaceToClusters)).
+		Watches(&coordinationv1.Lease{}, handler.EnqueueRequestsFromMapFunc(r.mapLeaseToCluster),
+			builder.WithPredicates(r.leaseRenewalPredicate())).
 		Complete(r)
 }
 
-// +kubebuilder:rbac:groups="",resources=events,verbs=create
-// +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;create;update
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;watch;list
+// +kubebuilder:rbac:groups="",namespace=system,resources=namespaces,verbs=patch
+// +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;watch;list;create;update
 // +kubebuilder:rbac:groups=cluster.open-cluster-management.io,resources=managedclusters,verbs=get;watch;list
+// +kubebuilder:rbac:groups=addon.open-cluster-management.io,resources=managedclusteraddons,verbs=get;watch;list
 // +kubebuilder:rbac:groups=ramendr.openshift.io,resources=drplacementcontrols,verbs=get;watch;list;patch
+// +kubebuilder:rbac:groups=ramendr.openshift.io,resources=drpolicies,verbs=get;watch;list
+// +kubebuilder:rbac:groups=rdrtrigger.redhat.com,resources=drtriggerpolicies,verbs=get;watch;list
+// +kubebuilder:rbac:groups=rdrtrigger.redhat.com,resources=failoverplans,verbs=get;watch;list;create
+// +kubebuilder:rbac:groups=rdrtrigger.redhat.com,resources=failoverplans/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=rdrtrigger.redhat.com,resources=failoversilences,verbs=get;watch;list
 // +kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=get;create
 // +kubebuilder:rbac:groups=authentication.k8s.io,resources=tokenreviews,verbs=create
 
 // Reconcile is watching ManagedClusters and will trigger a DRPlacementControl failover. Note, not eligible
 // events for failover. i.e., the cluster is not accepted by the hub, hasn't joined the hub, or is available. // Are
-// filtered out by event filtering Predicates.
+// filtered out by event filtering Predicates. The DRPlacementControls of an unavailable cluster are failed over once
+// they pass the failover rules of decide, the ones of an available cluster may be failed back.
 func (r *DRTriggerController) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	logger := log.FromContext(ctx).WithName("mc-controller")
 	ctx = log.IntoContext(ctx, logger)
@@ -83,9 +159,116 @@ func (r *DRTriggerController) Reconcile(ctx context.Context, req ctrl.Request) (
 		return ctrl.Result{}, nil
 	}
 
+	// global pause, the failover rules are evaluated without patching any dr control
+	paused, err := r.paused(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if paused {
+		logger.Info("operator paused, evaluating without patching dr controls")
+	}
+
+	// mass outage circuit breaker, i.e. a hub network partition marking the whole fleet unavailable
+	breaker, err := r.Breaker.evaluate(ctx, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if breaker.changed && breaker.open {
+		logger.Error(nil, "circuit breaker opened, automatic failovers paused", "reason", breaker.reason)
+	} else if breaker.changed {
+		logger.Info("circuit breaker closed, automatic failovers resumed", "reason", breaker.reason)
+		r.Recorder.Eventf(mc, corev1.EventTypeNormal, ReasonCircuitBreakerClosed,
+			"Automatic failover resumed, circuit breaker closed, %s", breaker.reason)
+	}
+
+	// clusters with unstable links, oscillating between available and unavailable, get stricter rules
+	flapping, changed := r.flaps.observe(mc, r.Flapping)
+	if changed && flapping {
+		logger.Info("managed cluster is flapping", "threshold", r.Flapping.Threshold, "window", r.Flapping.Window.String())
+		r.Recorder.Eventf(mc, corev1.EventTypeWarning, ReasonClusterFlapping,
+			"Managed cluster is flapping, its availability transitioned at least %d times within %s",
+			r.Flapping.Threshold, r.Flapping.Window)
+	} else if changed {
+		logger.Info("managed cluster is no longer flapping", "window", r.Flapping.Window.String())
+		r.Recorder.Eventf(mc, corev1.EventTypeNormal, ReasonClusterStable,
+			"Managed cluster is no longer flapping, its availability did not transition within %s", r.Flapping.Window)
+	}
+
+	// cluster lease staleness, an earlier, or a confirming, signal of an outage
+	lease, err := r.LeaseDetection.evaluate(ctx, r.Client, mc)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if r.LeaseDetection.Mode == LeaseModeEarly && lease.stale &&
+		meta.IsStatusConditionTrue(mc.Status.Conditions, clusterv1.ManagedClusterConditionAvailable) {
+		logger.Info("managed cluster lease is stale, marking unavailable", "renewed_at", lease.renewedAt)
+		r.Recorder.Eventf(mc, corev1.EventTypeWarning, ReasonClusterLeaseStale,
+			"Managed cluster lease %s not renewed since %s, marked as unavailable", r.LeaseDetection.LeaseName,
+			lease.renewedAt.Format(time.RFC3339))
+		mc = markUnavailable(mc, lease.renewedAt)
+	}
+
+	// plans of past outages are closed, their approvals no longer apply
+	if err := r.closeFailoverPlans(ctx, mc); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// dual failures of the DRPolicies, both of their clusters unavailable, leave no DR path
+	targets := map[string]*clusterv1.ManagedCluster{}
+	dualFailures, err := r.evaluateDualFailures(ctx, mc, targets)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
 	if meta.IsStatusConditionTrue(mc.Status.Conditions, clusterv1.ManagedClusterConditionAvailable) {
 		logger.Info("managed cluster is available, no failing over required")
-		return ctrl.Result{}, nil
+		var res ctrl.Result
+		switch {
+		case flapping:
+			// re-evaluated for clearing the flapping state once stable for a whole window, not failing back meanwhile
+			res = ctrl.Result{RequeueAfter: r.Flapping.Window}
+		case breaker.open:
+		default:
+			res, err = r.reconcileFailbacks(ctx, mc, paused)
+		}
+		if r.LeaseDetection.Mode == LeaseModeEarly && lease.found {
+			// re-evaluated once the lease is stale, unless renewed meanwhile
+			res.RequeueAfter = minRequeue(res.RequeueAfter, lease.staleIn+time.Second)
+		}
+		return res, err
+	}
+
+	if breaker.open {
+		logger.Info("circuit breaker open, not failing over", "reason", breaker.reason)
+		r.Recorder.Eventf(mc, corev1.EventTypeWarning, ReasonCircuitBreakerOpen,
+			"Automatic failover paused, circuit breaker open, %s", breaker.reason)
+		return ctrl.Result{RequeueAfter: breakerRequeueInterval}, nil
+	}
+
+	// cluster lease confirming the outage, a renewed lease means the registration agent still reaches the hub
+	if r.LeaseDetection.Mode == LeaseModeConfirm && lease.found && !lease.stale {
+		logger.Info("managed cluster lease renewed, outage not confirmed", "renewed_at", lease.renewedAt)
+		r.Recorder.Eventf(mc, corev1.EventTypeNormal, ReasonFailoverUnconfirmed,
+			"Failover held, managed cluster lease %s renewed at %s, the outage is not confirmed",
+			r.LeaseDetection.LeaseName, lease.renewedAt.Format(time.RFC3339))
+		return ctrl.Result{RequeueAfter: lease.staleIn + time.Second}, nil
+	}
+
+	// prometheus queries confirming the outage, all of them must agree the cluster is down
+	if r.Prometheus.enabled() {
+		query, err := r.Prometheus.confirmsOutage(ctx, mc.Name)
+		if err != nil {
+			logger.Error(err, "prometheus query failed, outage not confirmed", "query", query)
+			r.Recorder.Eventf(mc, corev1.EventTypeWarning, ReasonPrometheusQueryFailed,
+				"Failover held, prometheus query %s failed, the outage is not confirmed, %v", query, err)
+			return ctrl.Result{RequeueAfter: r.Prometheus.Interval}, nil
+		}
+		if query != "" {
+			logger.Info("prometheus query returned no result, outage not confirmed", "query", query)
+			r.Recorder.Eventf(mc, corev1.EventTypeNormal, ReasonFailoverUnconfirmed,
+				"Failover held, prometheus query %s returned no result, the outage is not confirmed", query)
+			return ctrl.Result{RequeueAfter: r.Prometheus.Interval}, nil
+		}
 	}
 
 	drControls := &ramenv1alpha1.DRPlacementControlList{}
@@ -93,57 +276,203 @@ func (r *DRTriggerController) Reconcile(ctx context.Context, req ctrl.Request) (
 		return ctrl.Result{}, err
 	}
 
+	policies, err := r.listPolicies(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	budget, err := r.loadFailoverBudget(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// agent only outages, i.e. a crashed agent, are told apart from whole cluster ones using the cluster addons health
+	outage, outageRecheck, err := r.Outages.classify(ctx, r.Client, mc)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	logger.Info("classified managed cluster outage", "outage", outage)
+
+	silenceList := &rdrtriggerv1alpha1.FailoverSilenceList{}
+	if err := r.Client.List(ctx, silenceList); err != nil {
+		return ctrl.Result{}, err
+	}
+	silences := activeSilences(ctx, silenceList.Items, mc, time.Now())
+
+	// clusters requiring approval are failed over using the approved failover plan of the current outage
+	plan, err := r.loadFailoverPlan(ctx, mc)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// higher priority dr controls are failed over first
+	sortByPriority(ctx, drControls.Items)
+	state := &failoverState{
+		mc:            mc,
+		policies:      policies,
+		paused:        paused,
+		flapping:      flapping,
+		outage:        outage,
+		outageRecheck: outageRecheck,
+		silences:      silences,
+		plan:          plan,
+		budget:        budget,
+		priorities:    &priorityGate{},
+		namespaces:    map[string]*corev1.Namespace{},
+		drPolicies:    map[string]*ramenv1alpha1.DRPolicy{},
+		targets:       targets,
+	}
+
 	var errs *multierror.Error
+	requeueAfter := r.RequeueInterval // re-evaluate periodically while the cluster stays unavailable
 	for _, drControl := range drControls.Items {
+		// dr controls running on current managed cluster, preferring it, failed over to it, or being moved to it
+		if !involvesCluster(drControl, mc.Name) {
+			continue
+		}
+		logger.Info("found dr control for managed cluster", "drpc_name", drControl.Name, "drpc_ns", drControl.Namespace)
+		if peer, found := dualFailures[drControl.Spec.DRPolicyRef.Name]; found {
+			r.Recorder.Eventf(&drControl, corev1.EventTypeWarning, ReasonDualFailure,
+				"Both DRPolicy %s clusters %s and %s are unavailable, there is no DR path",
+				drControl.Spec.DRPolicyRef.Name, mc.Name, peer)
+		}
 
-		// dr controls using current managed cluster
-		if drControl.Status.PreferredDecision.ClusterName == mc.Name {
-			logger.Info("found dr control for managed cluster",
-				"drpc_name", drControl.Name, "drpc_ns", drControl.Namespace)
-			// dr controls not already failed-over
-			if drControl.Spec.Action != ramenv1alpha1.ActionFailover {
-				// dr control in phase suitable for a failover
-				if isPhaseOkForFailover(drControl) {
-					// dr control peer is ready
-					if meta.IsStatusConditionTrue(drControl.Status.Conditions, ramenv1alpha1.ConditionPeerReady) {
-						// patch do control and initiate a failover process
-						if err := r.patchDRPlacementControl(ctx, drControl, ramenv1alpha1.ActionFailover); err != nil {
-							errs = multierror.Append(err, errs)
-						} else {
-							logger.Info("successfully patched dr control for a failover",
-								"drpc_name", drControl.Name, "drpc_ns", drControl.Namespace)
-							drApplicationFailoverMetric.WithLabelValues(mc.Name, drControl.Name, drControl.Namespace).Inc()
-						}
-					} else {
-						logger.Info("dr control peer not available for a failover",
-							"drpc_name", drControl.Name, "drpc_ns", drControl.Namespace)
-					}
-				} else {
-					logger.Info("dr control not in suitable phase for a failover", "drpc_name",
-						drControl.Name, "drpc_ns", drControl.Namespace, "dr_phase", drControl.Status.Phase)
-				}
-			} else {
-				logger.Info("dr control failover already initiated", "drpc_name",
-					drControl.Name, "drpc_ns", drControl.Namespace)
-			}
+		candidate, d, err := r.decide(ctx, state, drControl)
+		if err != nil {
+			errs = multierror.Append(err, errs)
+			continue
+		}
+		if d.pending {
+			state.priorities.pending(candidate.priority)
+		}
+		if d.requeueAfter > 0 {
+			requeueAfter = minRequeue(requeueAfter, d.requeueAfter)
+		}
+		if d.reason == ReasonFailoverDryRun {
+			// a would-be failover accounts for the limits, so dry-run reports the failovers that would be queued
+			state.budget.add(candidate.failoverCluster, time.Now())
+		}
+		if d.reason != ReasonFailoverTriggered {
+			r.reportDecision(ctx, state, candidate, d)
+			continue
 		}
+		if err := r.failover(ctx, state, candidate); err != nil {
+			errs = multierror.Append(err, errs)
+		}
+	}
+
+	if err := r.saveFailoverPlan(ctx, mc, plan); err != nil {
+		logger.Error(err, "failed saving failover plan")
+		errs = multierror.Append(err, errs)
 	}
 
-	return ctrl.Result{}, errs.ErrorOrNil()
+	return ctrl.Result{RequeueAfter: requeueAfter}, errs.ErrorOrNil()
 }
 
-// patchDRPlacementControl is used to patch a DRPlacementControl for triggering a failover process
-func (r *DRTriggerController) patchDRPlacementControl(ctx context.Context, control ramenv1alpha1.DRPlacementControl, action ramenv1alpha1.DRAction) error {
+// failover is used for failing over a DRPlacementControl that passed all the failover rules. The FailoverRecord is
+// created before patching, so the audit trail and the failover limits never miss a failover.
+func (r *DRTriggerController) failover(ctx context.Context, s *failoverState, c *failoverCandidate) error {
+	logger := log.FromContext(ctx)
+	control := c.control
+
+	breakGlassReason := ""
+	if len(c.bypassed) > 0 {
+		breakGlassReason = c.glass.reason
+	}
+	if err := r.createFailoverRecord(ctx, s.mc, control, c.policy, c.failoverCluster, breakGlassReason); err != nil {
+		logger.Error(err, "failed creating failover record", "drpc_name", control.Name, "drpc_ns", control.Namespace)
+		return err
+	}
+
+	// patch do control and initiate a failover process
+	if err := r.patchDRPlacementControl(ctx, control, ramenv1alpha1.ActionFailover, c.failoverCluster); err != nil {
+		r.recordEvent(s.mc, &control, corev1.EventTypeWarning, ReasonPatchFailed,
+			"Failed patching for a failover, managed cluster %s is unavailable: %v", s.mc.Name, err)
+		if err := r.deleteFailoverRecord(ctx, s.mc, control); err != nil {
+			logger.Error(err, "failed deleting failover record", "drpc_name", control.Name, "drpc_ns", control.Namespace)
+		}
+		return err
+	}
+
+	logger.Info("successfully patched dr control for a failover",
+		"drpc_name", control.Name, "drpc_ns", control.Namespace, "failover_cluster", c.failoverCluster)
+	s.budget.add(c.failoverCluster, time.Now())
+	if len(c.bypassed) > 0 {
+		logger.Info("break-glass failover, data loss is possible", "drpc_name", control.Name,
+			"drpc_ns", control.Namespace, "bypassed", c.bypassed, "reason", c.glass.reason)
+		drApplicationBreakGlassFailoverMetric.WithLabelValues(s.mc.Name, control.Name, control.Namespace).Inc()
+		r.recordEvent(s.mc, &control, corev1.EventTypeWarning, ReasonBreakGlassFailover,
+			"Break-glass failover bypassing %s, data loss is possible, reason: %s",
+			strings.Join(c.bypassed, " and "), c.glass.reason)
+	}
+	if c.convertInFlight {
+		r.recordEvent(s.mc, &control, corev1.EventTypeNormal, ReasonInFlightConverted,
+			"In-flight %s converted to a failover to %s, managed cluster %s is unavailable",
+			control.Status.Phase, c.failoverCluster, s.mc.Name)
+	}
+	r.recordEvent(s.mc, &control, corev1.EventTypeNormal, ReasonFailoverTriggered,
+		"Failover to %s initiated, managed cluster %s is unavailable", c.failoverCluster, s.mc.Name)
+	drApplicationFailoverMetric.WithLabelValues(s.mc.Name, control.Name, control.Namespace).Inc()
+	s.plan.record(control, c.failoverCluster, rdrtriggerv1alpha1.FailoverPlanEntryFailoverTriggered,
+		ReasonFailoverTriggered, "")
+	return nil
+}
+
+// mapDRControlToCluster is used for mapping a DRPlacementControl to reconcile requests for the cluster it runs on, and
+// for its preferred cluster when it is the destination of an in-flight operation, or of an automatic failback
+func mapDRControlToCluster(_ context.Context, obj client.Object) []reconcile.Request {
+	control, ok := obj.(*ramenv1alpha1.DRPlacementControl)
+	if !ok {
+		return nil
+	}
+
+	var requests []reconcile.Request
+	if current := currentCluster(*control); current != "" {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: current}})
+	}
+	destination := control.Spec.PreferredCluster
+	if (inFlight(*control) || failbackOptIn(*control)) && destination != "" && destination != currentCluster(*control) {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: destination}})
+	}
+	return requests
+}
+
+// currentCluster is a utility function returning the cluster a DRPlacementControl application runs on. Once Ramen
+// reports a failover as completed, the application runs on the failover cluster, otherwise on the preferred decision.
+func currentCluster(control ramenv1alpha1.DRPlacementControl) string {
+	if failedOver(control) {
+		return control.Spec.FailoverCluster
+	}
+	return control.Status.PreferredDecision.ClusterName
+}
+
+// failedOver is a utility function returning true if Ramen reports the failover of a DRPlacementControl as completed,
+// its application running on the failover cluster
+func failedOver(control ramenv1alpha1.DRPlacementControl) bool {
+	return control.Spec.Action == ramenv1alpha1.ActionFailover && control.Spec.FailoverCluster != "" &&
+		control.Status.Phase == ramenv1alpha1.FailedOver
+}
+
+// patchDRPlacementControl is used to patch a DRPlacementControl for triggering a failover process to the failover
+// cluster, recording the time of the automated action in the same patch
+func (r *DRTriggerController) patchDRPlacementControl(ctx context.Context, control ramenv1alpha1.DRPlacementControl, action ramenv1alpha1.DRAction, failoverCluster string) error {
 	drControlObj := &ramenv1alpha1.DRPlacementControl{}
 	drControlSubject := types.NamespacedName{Namespace: control.Namespace, Name: control.Name}
 	if err := r.Client.Get(ctx, drControlSubject, drControlObj); err != nil {
 		return err
 	}
 
-	failoverPatch := &ramenv1alpha1.DRPlacementControl{
-		Spec: ramenv1alpha1.DRPlacementControlSpec{
-			Action: action,
-		},
+	// the failover approval is consumed by the action, a later outage requires a new approval
+	spec := map[string]interface{}{"action": action}
+	if failoverCluster != "" {
+		spec["failoverCluster"] = failoverCluster
+	}
+	failoverPatch := map[string]interface{}{
+		"metadata": map[string]interface{}{"annotations": map[string]interface{}{
+			rdrtriggerv1alpha1.LastAutomatedActionAnnotation: time.Now().UTC().Format(time.RFC3339),
+			rdrtriggerv1alpha1.FailoverApprovedAnnotation:    nil,
+		}},
+		"spec": spec,
 	}
 
 	rawPatch, err := json.Marshal(failoverPatch)
@@ -158,17 +487,16 @@ func (r *DRTriggerController) patchDRPlacementControl(ctx context.Context, contr
 	return nil
 }
 
-// isPhaseOkForFailover is a utility function that returns true if the DRPlacementControl.Status.Spec is in a state
-// allowed for failing over. i.e., Deployed.
-func isPhaseOkForFailover(control ramenv1alpha1.DRPlacementControl) bool {
-	for _, state := range okToFailoverStates {
-		if state == control.Status.Phase {
-			return true
-		}
+// minRequeue is a utility function returning the shortest of two requeue durations, ignoring zero durations
+func minRequeue(current, candidate time.Duration) time.Duration {
+	if current == 0 || candidate < current {
+		return candidate
 	}
-	return false
+	return current
 }
 
 func init() {
-	metrics.Registry.MustRegister(drApplicationFailoverMetric)
+	metrics.Registry.MustRegister(
+		drApplicationFailoverMetric, drApplicationFailoverDryRunMetric, drApplicationFailoverQueuedMetric,
+		drApplicationFailoverTargetUnavailableMetric)
 }
@@ -6,10 +6,14 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	ramenv1alpha1 "github.com/ramendr/ramen/api/v1alpha1"
+	coordinationv1 "k8s.io/api/coordination/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
 	"path/filepath"
+	rdrtriggerv1alpha1 "regional-dr-trigger-operator/api/v1alpha1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/envtest"
 	"testing"
@@ -28,14 +32,21 @@ func TestController(t *testing.T) {
 var _ = BeforeSuite(func(ctx SpecContext) {
 	By("bootstrapping testing environment")
 	testEnv = &envtest.Environment{
-		CRDDirectoryPaths: []string{filepath.Join("testdata", "external_crds")},
+		CRDDirectoryPaths: []string{
+			filepath.Join("..", "..", "config", "crd", "bases"),
+			filepath.Join("testdata", "external_crds"),
+		},
+		ErrorIfCRDPathMissing: true,
 	}
 
 	// install the scheme
 	scheme := runtime.NewScheme()
 	Expect(clusterv1.Install(scheme)).To(Succeed())
+	Expect(addonv1alpha1.Install(scheme)).To(Succeed())
 	Expect(ramenv1alpha1.AddToScheme(scheme)).To(Succeed())
-	Expect(corev1.AddToScheme(scheme)).To(Succeed()) // i.e. Namespace
+	Expect(corev1.AddToScheme(scheme)).To(Succeed())         // i.e. Namespace
+	Expect(coordinationv1.AddToScheme(scheme)).To(Succeed()) // i.e. Lease
+	Expect(rdrtriggerv1alpha1.AddToScheme(scheme)).To(Succeed())
 
 	// start testing environment and get config for the client
 	cfg, err := testEnv.Start()
@@ -48,7 +59,7 @@ var _ = BeforeSuite(func(ctx SpecContext) {
 	Expect(err).NotTo(HaveOccurred())
 
 	// create the controller
-	drtController = &DRTriggerController{Client: testClient, Scheme: scheme}
+	drtController = &DRTriggerController{Client: testClient, Scheme: scheme, Recorder: &record.FakeRecorder{}}
 })
 
 var _ = AfterSuite(func() {
@@ -9,9 +9,11 @@ import (
 	ramenv1alpha1 "github.com/ramendr/ramen/api/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"time"
 )
 
 var _ = Context("DR Trigger Controller", func() {
@@ -119,6 +121,10 @@ var _ = Context("DR Trigger Controller", func() {
 		}}
 		Expect(testClient.Status().Update(ctx, mc)).To(Succeed())
 
+		By("Create a validated DRPolicy with an available peer")
+		peer := createAvailableCluster(ctx, testName+"-peer")
+		drPolicy := createDRPolicy(ctx, testName, mc.Name, peer.Name)
+
 		By("Create a Namespace for the right DRPolicyControl")
 		rightNs := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: testName + "right-ns"}}
 		Expect(testClient.Create(ctx, rightNs)).To(Succeed())
@@ -130,7 +136,8 @@ var _ = Context("DR Trigger Controller", func() {
 				Namespace: rightNs.Name,
 			},
 			Spec: ramenv1alpha1.DRPlacementControlSpec{
-				Action: ramenv1alpha1.ActionRelocate,
+				Action:      ramenv1alpha1.ActionRelocate,
+				DRPolicyRef: corev1.ObjectReference{Name: drPolicy.Name},
 			},
 		}
 		Expect(testClient.Create(ctx, rightDr))
@@ -163,7 +170,8 @@ var _ = Context("DR Trigger Controller", func() {
 				Namespace: wrongNs.Name,
 			},
 			Spec: ramenv1alpha1.DRPlacementControlSpec{
-				Action: ramenv1alpha1.ActionRelocate,
+				Action:      ramenv1alpha1.ActionRelocate,
+				DRPolicyRef: corev1.ObjectReference{Name: drPolicy.Name},
 			},
 		}
 		Expect(testClient.Create(ctx, wrongDr))
@@ -249,6 +257,10 @@ var _ = Context("DR Trigger Controller", func() {
 		}}
 		Expect(testClient.Status().Update(ctx, mc)).To(Succeed())
 
+		By("Create a validated DRPolicy with an available peer")
+		peer := createAvailableCluster(ctx, testName+"-peer")
+		drPolicy := createDRPolicy(ctx, testName, mc.Name, peer.Name)
+
 		By("Create a Namespace for the right DRPolicyControl")
 		rightNs := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: testName + "right-ns"}}
 		Expect(testClient.Create(ctx, rightNs)).To(Succeed())
@@ -260,7 +272,8 @@ var _ = Context("DR Trigger Controller", func() {
 				Namespace: rightNs.Name,
 			},
 			Spec: ramenv1alpha1.DRPlacementControlSpec{
-				Action: ramenv1alpha1.ActionRelocate,
+				Action:      ramenv1alpha1.ActionRelocate,
+				DRPolicyRef: corev1.ObjectReference{Name: drPolicy.Name},
 			},
 		}
 		Expect(testClient.Create(ctx, rightDr))
@@ -293,7 +306,8 @@ var _ = Context("DR Trigger Controller", func() {
 				Namespace: wrongNs.Name,
 			},
 			Spec: ramenv1alpha1.DRPlacementControlSpec{
-				Action: ramenv1alpha1.ActionRelocate,
+				Action:      ramenv1alpha1.ActionRelocate,
+				DRPolicyRef: corev1.ObjectReference{Name: drPolicy.Name},
 			},
 		}
 		Expect(testClient.Create(ctx, wrongDr))
@@ -379,6 +393,10 @@ var _ = Context("DR Trigger Controller", func() {
 		}}
 		Expect(testClient.Status().Update(ctx, mc)).To(Succeed())
 
+		By("Create a validated DRPolicy with an available peer")
+		peer := createAvailableCluster(ctx, testName+"-peer")
+		drPolicy := createDRPolicy(ctx, testName, mc.Name, peer.Name)
+
 		By("Create a Namespace for the right DRPolicyControl")
 		rightNs := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: testName + "right-ns"}}
 		Expect(testClient.Create(ctx, rightNs)).To(Succeed())
@@ -390,7 +408,8 @@ var _ = Context("DR Trigger Controller", func() {
 				Namespace: rightNs.Name,
 			},
 			Spec: ramenv1alpha1.DRPlacementControlSpec{
-				Action: ramenv1alpha1.ActionRelocate,
+				Action:      ramenv1alpha1.ActionRelocate,
+				DRPolicyRef: corev1.ObjectReference{Name: drPolicy.Name},
 			},
 		}
 		Expect(testClient.Create(ctx, rightDr))
@@ -423,7 +442,8 @@ var _ = Context("DR Trigger Controller", func() {
 				Namespace: wrongNs.Name,
 			},
 			Spec: ramenv1alpha1.DRPlacementControlSpec{
-				Action: ramenv1alpha1.ActionRelocate,
+				Action:      ramenv1alpha1.ActionRelocate,
+				DRPolicyRef: corev1.ObjectReference{Name: drPolicy.Name},
 			},
 		}
 		Expect(testClient.Create(ctx, wrongDr))
@@ -481,4 +501,124 @@ var _ = Context("DR Trigger Controller", func() {
 		Expect(testClient.Delete(ctx, rightNs)).To(Succeed())
 		Expect(testClient.Delete(ctx, mc)).To(Succeed())
 	})
+
+	It("should only report failovers when in dry-run mode", func(ctx SpecContext) {
+		testName := "dry-run-mode"
+
+		By("Create an unavailable ManagedCluster")
+		mc := createUnavailableCluster(ctx, testName)
+
+		By("Create a DRPlacementControl eligible for a failover")
+		drControl, ns := createDRControl(ctx, testName, mc.Name, nil, ramenv1alpha1.Deployed,
+			drCondition(ramenv1alpha1.ConditionPeerReady, metav1.ConditionTrue))
+
+		By("Reconcile for the MC with a dry-run controller")
+		recorder := record.NewFakeRecorder(10)
+		dryRunController := &DRTriggerController{Client: testClient, Scheme: drtController.Scheme, Recorder: recorder, DryRun: true}
+		res, err := dryRunController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)})
+		Expect(res.Requeue).To(BeFalse())
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Verify the DRPC was not failed-over")
+		Consistently(drAction(ctx, drControl)).Should(Equal(ramenv1alpha1.ActionRelocate))
+
+		By("Verify the would-be failover was recorded")
+		Expect(recorder.Events).To(Receive(ContainSubstring(ReasonFailoverDryRun)))
+
+		By("Cleanups")
+		Expect(testClient.Delete(ctx, drControl)).To(Succeed())
+		Expect(testClient.Delete(ctx, ns)).To(Succeed())
+		Expect(testClient.Delete(ctx, mc)).To(Succeed())
+	})
+
+	It("should record events on both the dr control and the managed cluster", func(ctx SpecContext) {
+		testName := "record-events"
+
+		By("Create an unavailable ManagedCluster")
+		mc := createUnavailableCluster(ctx, testName)
+
+		By("Create a DRPlacementControl eligible for a failover")
+		rightDr, rightNs := createDRControl(ctx, testName+"-right", mc.Name, nil, ramenv1alpha1.Deployed,
+			drCondition(ramenv1alpha1.ConditionPeerReady, metav1.ConditionTrue))
+
+		By("Create a DRPlacementControl not in a phase suitable for failing over")
+		phaseDr, phaseNs := createDRControl(ctx, testName+"-phase", mc.Name, nil, ramenv1alpha1.Initiating,
+			drCondition(ramenv1alpha1.ConditionPeerReady, metav1.ConditionTrue))
+
+		By("Create a DRPlacementControl with a peer not in a ready state")
+		peerDr, peerNs := createDRControl(ctx, testName+"-peer", mc.Name, nil, ramenv1alpha1.Deployed,
+			drCondition(ramenv1alpha1.ConditionPeerReady, metav1.ConditionFalse))
+
+		By("Reconcile for the MC with a recording controller")
+		recorder := record.NewFakeRecorder(20)
+		recordingController := &DRTriggerController{Client: testClient, Scheme: drtController.Scheme, Recorder: recorder}
+		res, err := recordingController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)})
+		Expect(res.Requeue).To(BeFalse())
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Verify the decisions were recorded for both the DRPCs and the MC")
+		events := recordedEvents(recorder)
+		Expect(events).To(HaveLen(6))
+		Expect(events).To(ContainElement(ContainSubstring(ReasonFailoverTriggered)))
+		Expect(events).To(ContainElement(ContainSubstring(ReasonFailoverSkippedPhase)))
+		Expect(events).To(ContainElement(ContainSubstring(ReasonFailoverSkippedPeerNotReady)))
+		Expect(events).To(ContainElement(ContainSubstring("DRPlacementControl " + rightNs.Name + "/" + rightDr.Name)))
+
+		By("Reconcile for the MC again")
+		_, err = recordingController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Verify the failed-over DRPC was recorded as already initiated")
+		Expect(recordedEvents(recorder)).To(ContainElement(ContainSubstring(ReasonFailoverAlreadyInitiated)))
+
+		By("Cleanups")
+		Expect(testClient.Delete(ctx, peerDr)).To(Succeed())
+		Expect(testClient.Delete(ctx, peerNs)).To(Succeed())
+		Expect(testClient.Delete(ctx, phaseDr)).To(Succeed())
+		Expect(testClient.Delete(ctx, phaseNs)).To(Succeed())
+		Expect(testClient.Delete(ctx, rightDr)).To(Succeed())
+		Expect(testClient.Delete(ctx, rightNs)).To(Succeed())
+		Expect(testClient.Delete(ctx, mc)).To(Succeed())
+	})
+
+	It("should failover dr controls becoming eligible while the managed cluster stays unavailable", func(ctx SpecContext) {
+		testName := "eligible-later"
+
+		By("Create an unavailable ManagedCluster")
+		mc := createUnavailableCluster(ctx, testName)
+
+		By("Create a DRPlacementControl with a peer not in a ready state")
+		drControl, ns := createDRControl(ctx, testName, mc.Name, nil, ramenv1alpha1.Deployed,
+			drCondition(ramenv1alpha1.ConditionPeerReady, metav1.ConditionFalse))
+
+		By("Reconcile for the MC with a requeueing controller")
+		requeueController := &DRTriggerController{
+			Client: testClient, Scheme: drtController.Scheme, Recorder: drtController.Recorder, RequeueInterval: 5 * time.Minute}
+		res, err := requeueController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Verify the DRPC was not failed-over and the MC was requeued")
+		Expect(drAction(ctx, drControl)()).To(Equal(ramenv1alpha1.ActionRelocate))
+		Expect(res.RequeueAfter).To(Equal(5 * time.Minute))
+
+		By("Update the DRPC peer to a ready state")
+		drControl.Status.Conditions = []metav1.Condition{drCondition(ramenv1alpha1.ConditionPeerReady, metav1.ConditionTrue)}
+		Expect(testClient.Status().Update(ctx, drControl)).To(Succeed())
+
+		By("Verify the DRPC is mapped to its preferred MC")
+		Expect(mapDRControlToCluster(ctx, drControl)).To(ContainElement(
+			ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)}))
+
+		By("Reconcile for the MC")
+		_, err = requeueController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Verify the DRPC was failed-over")
+		Expect(drAction(ctx, drControl)()).To(Equal(ramenv1alpha1.ActionFailover))
+
+		By("Cleanups")
+		Expect(testClient.Delete(ctx, drControl)).To(Succeed())
+		Expect(testClient.Delete(ctx, ns)).To(Succeed())
+		Expect(testClient.Delete(ctx, mc)).To(Succeed())
+	})
 })
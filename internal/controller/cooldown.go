@@ -0,0 +1,50 @@
+// Copyright (c) 2023 Red Hat, Inc.
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	ramenv1alpha1 "github.com/ramendr/ramen/api/v1alpha1"
+	rdrtriggerv1alpha1 "regional-dr-trigger-operator/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// lastActionTime is a utility function returning the time of the last action on the DRPlacementControl, the later of
+// the last automated failover recorded by the operator, and the start of the current action, i.e. a relocate.
+// Returns the zero time if neither is known.
+func lastActionTime(ctx context.Context, control ramenv1alpha1.DRPlacementControl) time.Time {
+	var last time.Time
+	if value, found := control.Annotations[rdrtriggerv1alpha1.LastAutomatedActionAnnotation]; found {
+		if at, err := time.Parse(time.RFC3339, value); err == nil {
+			last = at
+		} else {
+			log.FromContext(ctx).Info("ignoring malformed last automated action annotation", "drpc_name",
+				control.Name, "drpc_ns", control.Namespace, "value", value)
+		}
+	}
+	// no action is set for the initial deployment, and an in-flight action is the one being interrupted
+	if control.Spec.Action != "" && !inFlight(control) && control.Status.ActionStartTime != nil &&
+		control.Status.ActionStartTime.Time.After(last) {
+		last = control.Status.ActionStartTime.Time
+	}
+	return last
+}
+
+// cooldownRemaining returns the duration left until the DRPlacementControl can be failed over again, or 0 if the
+// cooldown passed since its last action. Prevents applications from bouncing between the DR peers during a cascading
+// incident.
+func (r *DRTriggerController) cooldownRemaining(ctx context.Context, control ramenv1alpha1.DRPlacementControl) time.Duration {
+	if r.Cooldown <= 0 {
+		return 0
+	}
+	last := lastActionTime(ctx, control)
+	if last.IsZero() {
+		return 0
+	}
+	if remaining := time.Until(last.Add(r.Cooldown)); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2023 Red Hat, Inc.
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	ramenv1alpha1 "github.com/ramendr/ramen/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	rdrtriggerv1alpha1 "regional-dr-trigger-operator/api/v1alpha1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"time"
+)
+
+var _ = Context("Failover Cooldown", func() {
+	It("should not failover dr controls relocated within the cooldown", func(ctx SpecContext) {
+		testName := "cooldown"
+
+		By("Create an unavailable ManagedCluster")
+		mc := createUnavailableCluster(ctx, testName)
+
+		By("Create a DRPlacementControl relocated a minute ago")
+		drControl, ns := createDRControl(ctx, testName, mc.Name, nil, ramenv1alpha1.Relocated,
+			drCondition(ramenv1alpha1.ConditionPeerReady, metav1.ConditionTrue))
+		relocatedAt := metav1.NewTime(time.Now().Add(-time.Minute))
+		drControl.Status.ActionStartTime = &relocatedAt
+		Expect(testClient.Status().Update(ctx, drControl)).To(Succeed())
+
+		By("Reconcile for the MC with an hour cooldown")
+		recorder := record.NewFakeRecorder(10)
+		cooldownController := &DRTriggerController{Client: testClient, Scheme: drtController.Scheme, Recorder: recorder,
+			Cooldown: time.Hour}
+		res, err := cooldownController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Verify the DRPC was not failed-over and requeued for the end of the cooldown")
+		Expect(drAction(ctx, drControl)()).To(Equal(ramenv1alpha1.ActionRelocate))
+		Expect(recordedEvents(recorder)).To(ContainElement(ContainSubstring(ReasonFailoverSkippedCooldown)))
+		Expect(res.RequeueAfter).To(BeNumerically(">", 58*time.Minute))
+
+		By("Reconcile for the MC with no cooldown")
+		_, err = drtController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Verify the DRPC was failed-over and the automated action time was recorded")
+		Expect(testClient.Get(ctx, client.ObjectKeyFromObject(drControl), drControl)).To(Succeed())
+		Expect(drControl.Spec.Action).To(Equal(ramenv1alpha1.ActionFailover))
+		Expect(drControl.Annotations).To(HaveKey(rdrtriggerv1alpha1.LastAutomatedActionAnnotation))
+
+		By("Cleanups")
+		Expect(testClient.Delete(ctx, drControl)).To(Succeed())
+		Expect(testClient.Delete(ctx, ns)).To(Succeed())
+		Expect(testClient.Delete(ctx, mc)).To(Succeed())
+	})
+})
@@ -0,0 +1,31 @@
+// Copyright (c) 2023 Red Hat, Inc.
+
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// parseCronSchedule is used for parsing a standard 5 fields cron schedule, evaluated in UTC
+func parseCronSchedule(spec string) (cron.Schedule, error) {
+	schedule, err := cron.ParseStandard(spec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cron schedule %q: %w", spec, err)
+	}
+	return schedule, nil
+}
+
+// lastFiredWithin returns the last time the schedule fired within the duration before the given time, or the zero
+// time if it did not. The firings are walked forward from the start of the duration, a schedule never firing, i.e. on
+// february 30th, has no next firing.
+func lastFiredWithin(schedule cron.Schedule, now time.Time, duration time.Duration) time.Time {
+	now = now.UTC()
+	var fired time.Time
+	for next := schedule.Next(now.Add(-duration)); !next.IsZero() && !next.After(now); next = schedule.Next(next) {
+		fired = next
+	}
+	return fired
+}
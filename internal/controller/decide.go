@@ -0,0 +1,387 @@
+// Copyright (c) 2023 Red Hat, Inc.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	ramenv1alpha1 "github.com/ramendr/ramen/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	rdrtriggerv1alpha1 "regional-dr-trigger-operator/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Reasons of decisions only logged, without recording an event
+const (
+	// reasonFailoverDisabled is used when the policy of a DRPlacementControl disables automatic failover
+	reasonFailoverDisabled = "FailoverDisabled"
+	// reasonFailoverPostponed is used when the ManagedCluster was unavailable for less than the grace period
+	reasonFailoverPostponed = "FailoverPostponed"
+)
+
+// failoverState is the state shared by the failover decisions of the DRPlacementControls of an unavailable
+// ManagedCluster within a reconcile
+type failoverState struct {
+	mc            *clusterv1.ManagedCluster
+	policies      []rdrtriggerv1alpha1.DRTriggerPolicy
+	paused        bool
+	flapping      bool
+	outage        OutageClass
+	outageRecheck time.Duration
+	silences      []activeFailoverSilence
+	plan          *failoverPlan
+	budget        *failoverBudget
+	priorities    *priorityGate
+	namespaces    map[string]*corev1.Namespace
+	drPolicies    map[string]*ramenv1alpha1.DRPolicy
+	targets       map[string]*clusterv1.ManagedCluster
+}
+
+// failoverCandidate is a DRPlacementControl evaluated for a failover, with what the rules it passed found about it
+type failoverCandidate struct {
+	control         ramenv1alpha1.DRPlacementControl
+	policy          *rdrtriggerv1alpha1.DRTriggerPolicy
+	priority        int
+	secondFailure   bool
+	convertInFlight bool
+	glass           *breakGlass
+	glassIgnored    string
+	bypassed        []string
+	failoverCluster string
+	target          *clusterv1.ManagedCluster
+}
+
+// decision is the outcome of the failover rules for a DRPlacementControl
+type decision struct {
+	// reason is the event reason, ReasonFailoverTriggered if the DRPlacementControl passed all the rules
+	reason string
+	// eventType is the type of the event, empty records no event
+	eventType string
+	// message is the event message
+	message string
+	// planState is the state of the DRPlacementControl entry in the FailoverPlan, empty records it as gated
+	planState rdrtriggerv1alpha1.FailoverPlanEntryState
+	// planMessage is the message of the FailoverPlan entry
+	planMessage string
+	// requeueAfter is the duration after which the decision may change, 0 if only a watched event may change it
+	requeueAfter time.Duration
+	// pending holds the failovers of lower priority DRPlacementControls
+	pending bool
+	// metric is the counter incremented for the decision, nil if there is none
+	metric prometheus.Counter
+	// glassIgnored is the reason a requested break-glass was ignored, recorded with the decision
+	glassIgnored string
+}
+
+// failoverRule is a rule a DRPlacementControl must pass for a failover. Returns the decision if the DRPlacementControl
+// does not pass it, or nil if it does.
+type failoverRule func(ctx context.Context, s *failoverState, c *failoverCandidate) (*decision, error)
+
+// decide is used for evaluating the failover rules for a DRPlacementControl of the unavailable ManagedCluster, in
+// order. The first rule not passed decides, a DRPlacementControl passing all of them is failed over.
+func (r *DRTriggerController) decide(ctx context.Context, s *failoverState, control ramenv1alpha1.DRPlacementControl) (*failoverCandidate, *decision, error) {
+	c := &failoverCandidate{
+		control:       control,
+		policy:        selectPolicy(ctx, s.policies, s.mc, control),
+		priority:      failoverPriority(ctx, control),
+		secondFailure: failedOver(control),
+	}
+	c.glass, c.glassIgnored = r.breakGlassFor(s.mc, control)
+
+	rules := []failoverRule{
+		r.policyRule,
+		r.failoverInitiatedRule,
+		r.optOutRule,
+		r.inFlightRule,
+		r.phaseRule,
+		r.conditionsRule,
+		r.drPolicyRule,
+		r.gracePeriodRule,
+		r.outageRule,
+		r.flappingRule,
+		r.cooldownRule,
+		r.targetRule,
+		r.silenceRule,
+		r.pauseRule,
+		r.approvalRule,
+		r.priorityRule,
+		r.limitsRule,
+		r.dryRunRule,
+	}
+	for _, rule := range rules {
+		if d, err := rule(ctx, s, c); err != nil || d != nil {
+			return c, d, err
+		}
+	}
+	return c, &decision{reason: ReasonFailoverTriggered, pending: true}, nil
+}
+
+// reportDecision is used for reporting the decision for a DRPlacementControl not failed over, as an event, in the
+// metrics, and in the FailoverPlan
+func (r *DRTriggerController) reportDecision(ctx context.Context, s *failoverState, c *failoverCandidate, d *decision) {
+	log.FromContext(ctx).Info("dr control not failed over", "drpc_name", c.control.Name,
+		"drpc_ns", c.control.Namespace, "policy", policyName(c.policy), "reason", d.reason, "message", d.message)
+	r.recordBreakGlassIgnored(s.mc, &c.control, d.glassIgnored)
+	if d.eventType != "" {
+		r.recordEvent(s.mc, &c.control, d.eventType, d.reason, "%s", d.message)
+	}
+	if d.metric != nil {
+		d.metric.Inc()
+	}
+	// every decision is listed in the plan, the ones without a plan state as gated by their message
+	state, message := d.planState, d.planMessage
+	if state == "" {
+		state, message = rdrtriggerv1alpha1.FailoverPlanEntryGated, d.message
+	}
+	s.plan.record(c.control, c.failoverCluster, state, d.reason, message)
+}
+
+// policyRule requires the policy to allow automatic failover
+func (r *DRTriggerController) policyRule(_ context.Context, _ *failoverState, c *failoverCandidate) (*decision, error) {
+	if c.policy.IsEnabled() {
+		return nil, nil
+	}
+	return &decision{reason: reasonFailoverDisabled,
+		message: fmt.Sprintf("Failover skipped, policy %s disables automatic failover", policyName(c.policy))}, nil
+}
+
+// failoverInitiatedRule requires the DRPlacementControl not to be failed over already, unless failed over to the
+// unavailable cluster. A failover not reaching the release phase holds the lower priorities.
+func (r *DRTriggerController) failoverInitiatedRule(_ context.Context, s *failoverState, c *failoverCandidate) (*decision, error) {
+	if c.control.Spec.Action != ramenv1alpha1.ActionFailover || c.secondFailure {
+		return nil, nil
+	}
+	return &decision{reason: ReasonFailoverAlreadyInitiated, eventType: corev1.EventTypeNormal,
+		message: fmt.Sprintf("Failover already initiated, managed cluster %s is unavailable", s.mc.Name),
+		pending: !phaseReached(c.control.Status.Phase, r.releasePhase())}, nil
+}
+
+// optOutRule requires the DRPlacementControl or its Namespace not to opt out of automatic failover
+func (r *DRTriggerController) optOutRule(ctx context.Context, s *failoverState, c *failoverCandidate) (*decision, error) {
+	reason, err := r.autoFailoverSkipReason(ctx, c.control, s.namespaces)
+	if err != nil || reason == "" {
+		return nil, err
+	}
+	return &decision{reason: ReasonFailoverSkippedOptOut, eventType: corev1.EventTypeNormal,
+		message: fmt.Sprintf("Failover skipped, %s", reason)}, nil
+}
+
+// inFlightRule handles DRPlacementControls in the middle of an operation, i.e. a relocate, using the policy in-flight
+// strategy. Converted operations pass, bypassing the phase rule.
+func (r *DRTriggerController) inFlightRule(_ context.Context, s *failoverState, c *failoverCandidate) (*decision, error) {
+	if !inFlight(c.control) {
+		return nil, nil
+	}
+	phase := c.control.Status.Phase
+	switch c.policy.GetInFlightStrategy() {
+	case rdrtriggerv1alpha1.InFlightFailover:
+		c.convertInFlight = true
+		return nil, nil
+	case rdrtriggerv1alpha1.InFlightEscalate:
+		return &decision{reason: ReasonInFlightEscalated, eventType: corev1.EventTypeWarning,
+			message: fmt.Sprintf("In-flight %s escalated, managed cluster %s is unavailable, a decision by an "+
+				"administrator is required", phase, s.mc.Name),
+			metric: drApplicationInFlightEscalatedMetric.WithLabelValues(s.mc.Name, c.control.Name, c.control.Namespace)}, nil
+	default:
+		return &decision{reason: ReasonInFlightWaiting, eventType: corev1.EventTypeNormal,
+			message: fmt.Sprintf("In-flight %s left as is, waiting for managed cluster %s to recover", phase, s.mc.Name)}, nil
+	}
+}
+
+// phaseRule requires the DRPlacementControl to be in a phase suitable for a failover, a second failure is failed over
+// from FailedOver. A break-glass bypasses it.
+func (r *DRTriggerController) phaseRule(_ context.Context, _ *failoverState, c *failoverCandidate) (*decision, error) {
+	phase := c.control.Status.Phase
+	if c.secondFailure || c.convertInFlight || isPhaseOkForFailover(c.policy, c.control) {
+		return nil, nil
+	}
+	if c.glass != nil {
+		c.bypassed = append(c.bypassed, fmt.Sprintf("phase %s", phase))
+		return nil, nil
+	}
+	return &decision{reason: ReasonFailoverSkippedPhase, eventType: corev1.EventTypeNormal,
+		message:   fmt.Sprintf("Failover skipped, phase %q is not suitable for a failover", phase),
+		planState: rdrtriggerv1alpha1.FailoverPlanEntryGated, glassIgnored: c.glassIgnored,
+		planMessage: fmt.Sprintf("phase %s is not suitable for a failover", phase)}, nil
+}
+
+// conditionsRule requires the DRPlacementControl required conditions to be met, i.e. peer is ready. A break-glass
+// bypasses it.
+func (r *DRTriggerController) conditionsRule(_ context.Context, _ *failoverState, c *failoverCandidate) (*decision, error) {
+	condition := unmetCondition(c.policy, c.control)
+	if condition == "" {
+		return nil, nil
+	}
+	if c.glass != nil {
+		c.bypassed = append(c.bypassed, fmt.Sprintf("condition %s", condition))
+		return nil, nil
+	}
+	return &decision{reason: ReasonFailoverSkippedPeerNotReady, eventType: corev1.EventTypeNormal,
+		message:   fmt.Sprintf("Failover skipped, condition %s is not met", condition),
+		planState: rdrtriggerv1alpha1.FailoverPlanEntryGated, glassIgnored: c.glassIgnored,
+		planMessage: fmt.Sprintf("condition %s is not met", condition)}, nil
+}
+
+// drPolicyRule requires the failover cluster to be resolved from a validated DRPolicy
+func (r *DRTriggerController) drPolicyRule(ctx context.Context, s *failoverState, c *failoverCandidate) (*decision, error) {
+	failoverCluster, reason, err := r.resolveFailoverCluster(ctx, c.control, s.mc.Name, s.drPolicies)
+	if err != nil {
+		return nil, err
+	}
+	if reason != "" {
+		return &decision{reason: ReasonFailoverSkippedDRPolicy, eventType: corev1.EventTypeWarning,
+			message: fmt.Sprintf("Failover skipped, %s", reason)}, nil
+	}
+	target, err := r.getTargetCluster(ctx, failoverCluster, s.targets)
+	if err != nil {
+		return nil, err
+	}
+	c.failoverCluster, c.target = failoverCluster, target
+	return nil, nil
+}
+
+// gracePeriodRule requires the ManagedCluster to be unavailable for the policy grace period, flapping clusters for the
+// longer flapping one. A postponed failover holds the lower priorities.
+func (r *DRTriggerController) gracePeriodRule(_ context.Context, s *failoverState, c *failoverCandidate) (*decision, error) {
+	grace := gracePeriod(c.policy, s.mc)
+	if s.flapping {
+		grace = max(grace, flappingGracePeriod(c.policy))
+	}
+	remaining := grace - unavailableFor(s.mc)
+	if remaining <= 0 {
+		return nil, nil
+	}
+	return &decision{reason: reasonFailoverPostponed, requeueAfter: remaining, pending: true,
+		message: fmt.Sprintf("Failover postponed, managed cluster %s unavailable for less than the %s grace period, "+
+			"%s remaining", s.mc.Name, grace, remaining.Round(time.Second))}, nil
+}
+
+// outageRule requires the outage to be classified as a whole cluster outage, and not an agent only one, if required by
+// the policy
+func (r *DRTriggerController) outageRule(_ context.Context, s *failoverState, c *failoverCandidate) (*decision, error) {
+	if !c.policy.Spec.RequireWholeClusterOutage || s.outage == OutageWholeCluster {
+		return nil, nil
+	}
+	d := &decision{reason: ReasonFailoverUnconfirmed, eventType: corev1.EventTypeNormal,
+		message: fmt.Sprintf("Failover held, the outage of managed cluster %s is classified as %s, the policy "+
+			"requires %s", s.mc.Name, s.outage, OutageWholeCluster),
+		planState: rdrtriggerv1alpha1.FailoverPlanEntryGated, planMessage: fmt.Sprintf("outage classified as %s", s.outage)}
+	if s.outageRecheck > 0 {
+		d.requeueAfter = s.outageRecheck + time.Second
+	}
+	return d, nil
+}
+
+// flappingRule requires the failover of a flapping cluster to be approved, if required by the policy
+func (r *DRTriggerController) flappingRule(_ context.Context, s *failoverState, c *failoverCandidate) (*decision, error) {
+	if !s.flapping || !awaitingApproval(c.policy, c.control) {
+		return nil, nil
+	}
+	return &decision{reason: ReasonFailoverAwaitingApproval, eventType: corev1.EventTypeWarning,
+		message: fmt.Sprintf("Failover awaiting approval, managed cluster %s is flapping, approve using the %s annotation",
+			s.mc.Name, rdrtriggerv1alpha1.FailoverApprovedAnnotation)}, nil
+}
+
+// cooldownRule requires the DRPlacementControl not to be acted on within the cooldown, preventing ping-pong between
+// the DR peers
+func (r *DRTriggerController) cooldownRule(ctx context.Context, _ *failoverState, c *failoverCandidate) (*decision, error) {
+	remaining := r.cooldownRemaining(ctx, c.control)
+	if remaining <= 0 {
+		return nil, nil
+	}
+	return &decision{reason: ReasonFailoverSkippedCooldown, eventType: corev1.EventTypeWarning, requeueAfter: remaining,
+		message: fmt.Sprintf("Failover skipped, the DRPlacementControl was acted on within the %s cooldown, %s remaining",
+			r.Cooldown, remaining.Round(time.Second))}, nil
+}
+
+// targetRule requires the failover cluster to be healthy, failing over to an unavailable peer is worse than not
+// failing over
+func (r *DRTriggerController) targetRule(_ context.Context, s *failoverState, c *failoverCandidate) (*decision, error) {
+	reason := targetUnavailableReason(c.failoverCluster, c.target)
+	if reason == "" {
+		return nil, nil
+	}
+	return &decision{reason: ReasonFailoverSkippedTargetUnavailable, eventType: corev1.EventTypeWarning,
+		message:   fmt.Sprintf("Failover skipped, %s", reason),
+		planState: rdrtriggerv1alpha1.FailoverPlanEntryGated, planMessage: reason,
+		metric: drApplicationFailoverTargetUnavailableMetric.WithLabelValues(
+			s.mc.Name, c.control.Name, c.control.Namespace, c.failoverCluster)}, nil
+}
+
+// silenceRule only reports the failover decision while the DRPlacementControl is silenced, i.e. during planned
+// maintenance
+func (r *DRTriggerController) silenceRule(ctx context.Context, s *failoverState, c *failoverCandidate) (*decision, error) {
+	silence, remaining := silencing(ctx, s.silences, c.control)
+	if silence == nil {
+		return nil, nil
+	}
+	return &decision{reason: ReasonFailoverSilenced, eventType: corev1.EventTypeNormal, requeueAfter: remaining,
+		message: fmt.Sprintf("Silenced by FailoverSilence %s (%s), would have initiated a failover to %s, managed "+
+			"cluster %s is unavailable", silence.Name, silence.Spec.Reason, c.failoverCluster, s.mc.Name),
+		planState:   rdrtriggerv1alpha1.FailoverPlanEntryGated,
+		planMessage: fmt.Sprintf("silenced by FailoverSilence %s", silence.Name),
+		metric: drApplicationFailoverSilencedMetric.WithLabelValues(
+			s.mc.Name, c.control.Name, c.control.Namespace, silence.Name)}, nil
+}
+
+// pauseRule only reports the failover decision while the operator is paused
+func (r *DRTriggerController) pauseRule(_ context.Context, s *failoverState, c *failoverCandidate) (*decision, error) {
+	if !s.paused {
+		return nil, nil
+	}
+	return &decision{reason: ReasonFailoverPaused, eventType: corev1.EventTypeNormal,
+		message: fmt.Sprintf("Operator paused, would have initiated a failover to %s, managed cluster %s is unavailable",
+			c.failoverCluster, s.mc.Name)}, nil
+}
+
+// approvalRule requires the FailoverPlan of the outage to be approved, for clusters requiring approval
+func (r *DRTriggerController) approvalRule(_ context.Context, s *failoverState, c *failoverCandidate) (*decision, error) {
+	if s.plan.approved() {
+		return nil, nil
+	}
+	return &decision{reason: ReasonFailoverAwaitingApproval, eventType: corev1.EventTypeWarning,
+		message:   fmt.Sprintf("Failover to %s awaiting approval of FailoverPlan %s", c.failoverCluster, s.plan.plan.Name),
+		planState: rdrtriggerv1alpha1.FailoverPlanEntryAwaitingApproval}, nil
+}
+
+// priorityRule requires the higher priority DRPlacementControls to reach the release phase
+func (r *DRTriggerController) priorityRule(_ context.Context, s *failoverState, c *failoverCandidate) (*decision, error) {
+	if !s.priorities.holds(c.priority) {
+		return nil, nil
+	}
+	return &decision{reason: ReasonFailoverHeld, eventType: corev1.EventTypeNormal, requeueAfter: priorityRequeueInterval,
+		message:     fmt.Sprintf("Failover held until higher priority DRPlacementControls are %s", r.releasePhase()),
+		planState:   rdrtriggerv1alpha1.FailoverPlanEntryGated,
+		planMessage: "held until higher priority DRPlacementControls are released"}, nil
+}
+
+// limitsRule requires the failover to be within the failover limits, protecting the surviving clusters. A queued
+// failover holds the lower priorities.
+func (r *DRTriggerController) limitsRule(_ context.Context, s *failoverState, c *failoverCandidate) (*decision, error) {
+	if s.budget.allows(c.failoverCluster) {
+		return nil, nil
+	}
+	return &decision{reason: ReasonFailoverQueued, eventType: corev1.EventTypeWarning,
+		message:      fmt.Sprintf("Failover queued, the failover limits within %s were reached", r.Limits.Window),
+		planState:    rdrtriggerv1alpha1.FailoverPlanEntryGated,
+		planMessage:  "queued for exceeding the failover limits",
+		requeueAfter: s.budget.retryAfter(), pending: true,
+		metric: drApplicationFailoverQueuedMetric.WithLabelValues(s.mc.Name, c.control.Name, c.control.Namespace)}, nil
+}
+
+// dryRunRule only reports the failover decision in dry-run mode. Evaluated last, so only failovers passing all the
+// other rules are reported. The would-be failover holds the lower priorities and accounts for the limits like a real
+// one.
+func (r *DRTriggerController) dryRunRule(_ context.Context, s *failoverState, c *failoverCandidate) (*decision, error) {
+	if !r.DryRun && !c.policy.Spec.DryRun {
+		return nil, nil
+	}
+	return &decision{reason: ReasonFailoverDryRun, eventType: corev1.EventTypeNormal, pending: true,
+		message: fmt.Sprintf("Dry-run, would have initiated a failover to %s, managed cluster %s is unavailable",
+			c.failoverCluster, s.mc.Name),
+		metric: drApplicationFailoverDryRunMetric.WithLabelValues(s.mc.Name, c.control.Name, c.control.Namespace)}, nil
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2023 Red Hat, Inc.
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	ramenv1alpha1 "github.com/ramendr/ramen/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	rdrtriggerv1alpha1 "regional-dr-trigger-operator/api/v1alpha1"
+	"time"
+)
+
+var _ = Context("Failover Rules", func() {
+	It("should decide using the first failover rule not passed, in order", func(ctx SpecContext) {
+		testName := "decide"
+
+		By("Create an unavailable ManagedCluster and a DRPlacementControl eligible for a failover")
+		mc := createUnavailableCluster(ctx, testName)
+		drControl, ns := createDRControl(ctx, testName, mc.Name, nil, ramenv1alpha1.Deployed,
+			drCondition(ramenv1alpha1.ConditionPeerReady, metav1.ConditionTrue))
+
+		decideController := &DRTriggerController{Client: testClient, Scheme: drtController.Scheme,
+			Recorder: record.NewFakeRecorder(10)}
+		policy := defaultPolicy.DeepCopy()
+		policy.Name = testName
+		s := &failoverState{
+			mc:         mc,
+			policies:   []rdrtriggerv1alpha1.DRTriggerPolicy{*policy},
+			budget:     &failoverBudget{perTarget: map[string]int{}},
+			priorities: &priorityGate{},
+			namespaces: map[string]*corev1.Namespace{},
+			drPolicies: map[string]*ramenv1alpha1.DRPolicy{},
+			targets:    map[string]*clusterv1.ManagedCluster{},
+		}
+		decided := func() string {
+			_, d, err := decideController.decide(ctx, s, *drControl)
+			Expect(err).NotTo(HaveOccurred())
+			return d.reason
+		}
+
+		By("Verify a dr control passing all the rules is failed over to its peer")
+		candidate, d, err := decideController.decide(ctx, s, *drControl)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(d.reason).To(Equal(ReasonFailoverTriggered))
+		Expect(candidate.failoverCluster).To(Equal(testName + "-peer"))
+
+		By("Verify each rule decides ahead of the rules following it")
+		decideController.DryRun = true
+		Expect(decided()).To(Equal(ReasonFailoverDryRun))
+
+		s.budget = &failoverBudget{limits: FailoverLimits{Global: 1, Window: time.Hour}, perTarget: map[string]int{}}
+		s.budget.add("", time.Now())
+		Expect(decided()).To(Equal(ReasonFailoverQueued))
+
+		s.priorities.pending(1)
+		Expect(decided()).To(Equal(ReasonFailoverHeld))
+
+		s.plan = &failoverPlan{plan: &rdrtriggerv1alpha1.FailoverPlan{ObjectMeta: metav1.ObjectMeta{Name: testName}}}
+		Expect(decided()).To(Equal(ReasonFailoverAwaitingApproval))
+
+		s.paused = true
+		Expect(decided()).To(Equal(ReasonFailoverPaused))
+
+		s.silences = []activeFailoverSilence{{silence: &rdrtriggerv1alpha1.FailoverSilence{
+			ObjectMeta: metav1.ObjectMeta{Name: testName}}, remaining: time.Hour}}
+		Expect(decided()).To(Equal(ReasonFailoverSilenced))
+
+		s.policies[0].Spec.RequireWholeClusterOutage = true
+		s.outage = OutageAgentOnly
+		Expect(decided()).To(Equal(ReasonFailoverUnconfirmed))
+
+		s.policies[0].Spec.UnavailableGracePeriod = &metav1.Duration{Duration: time.Hour}
+		Expect(decided()).To(Equal(reasonFailoverPostponed))
+
+		s.policies[0].Spec.RequiredConditions = []string{ramenv1alpha1.ConditionAvailable}
+		Expect(decided()).To(Equal(ReasonFailoverSkippedPeerNotReady))
+
+		s.policies[0].Spec.AllowedPhases = []ramenv1alpha1.DRState{ramenv1alpha1.Relocated}
+		Expect(decided()).To(Equal(ReasonFailoverSkippedPhase))
+
+		disabled := false
+		s.policies[0].Spec.Enabled = &disabled
+		Expect(decided()).To(Equal(reasonFailoverDisabled))
+
+		By("Cleanups")
+		Expect(testClient.Delete(ctx, drControl)).To(Succeed())
+		Expect(testClient.Delete(ctx, ns)).To(Succeed())
+		Expect(testClient.Delete(ctx, mc)).To(Succeed())
+	})
+})
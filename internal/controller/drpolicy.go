@@ -0,0 +1,53 @@
+// Copyright (c) 2023 Red Hat, Inc.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	ramenv1alpha1 "github.com/ramendr/ramen/api/v1alpha1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// resolveFailoverCluster is used for resolving the cluster a DRPlacementControl is failed over to, from its DRPolicy.
+// A failover cluster already set on the DRPlacementControl is kept if it is a peer in the DRPolicy, otherwise the
+// first peer of the unavailable cluster is picked. Returns the failover cluster, or the reason it was not resolved.
+// DRPolicies are fetched once per reconcile using the drPolicies map.
+func (r *DRTriggerController) resolveFailoverCluster(ctx context.Context, control ramenv1alpha1.DRPlacementControl, failedCluster string, drPolicies map[string]*ramenv1alpha1.DRPolicy) (string, string, error) {
+	policyName := control.Spec.DRPolicyRef.Name
+	if policyName == "" {
+		return "", "the DRPlacementControl does not reference a DRPolicy", nil
+	}
+
+	drPolicy, found := drPolicies[policyName]
+	if !found {
+		drPolicy = &ramenv1alpha1.DRPolicy{}
+		if err := r.Client.Get(ctx, client.ObjectKey{Name: policyName}, drPolicy); err != nil {
+			if k8serrors.IsNotFound(err) {
+				return "", fmt.Sprintf("DRPolicy %s not found", policyName), nil
+			}
+			return "", "", err
+		}
+		drPolicies[policyName] = drPolicy
+	}
+
+	if !meta.IsStatusConditionTrue(drPolicy.Status.Conditions, ramenv1alpha1.DRPolicyValidated) {
+		return "", fmt.Sprintf("DRPolicy %s is not validated", policyName), nil
+	}
+
+	current := control.Spec.FailoverCluster
+	if current != "" && current != failedCluster && slices.Contains(drPolicy.Spec.DRClusters, current) {
+		return current, "", nil
+	}
+
+	for _, cluster := range drPolicy.Spec.DRClusters {
+		if cluster != failedCluster {
+			return cluster, "", nil
+		}
+	}
+	return "", fmt.Sprintf("DRPolicy %s has no peer cluster for %s", policyName, failedCluster), nil
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2023 Red Hat, Inc.
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	ramenv1alpha1 "github.com/ramendr/ramen/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Context("DR Policy", func() {
+	It("should set the failover cluster to the dr policy peer", func(ctx SpecContext) {
+		testName := "drpolicy-peer"
+
+		By("Create an unavailable ManagedCluster")
+		mc := createUnavailableCluster(ctx, testName)
+
+		By("Create a DRPlacementControl without a failover cluster")
+		drControl, ns := createDRControl(ctx, testName, mc.Name, nil, ramenv1alpha1.Deployed,
+			drCondition(ramenv1alpha1.ConditionPeerReady, metav1.ConditionTrue))
+		Expect(drControl.Spec.FailoverCluster).To(BeEmpty())
+
+		By("Reconcile for the MC")
+		_, err := drtController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Verify the DRPC was failed-over to the peer cluster")
+		Expect(testClient.Get(ctx, client.ObjectKeyFromObject(drControl), drControl)).To(Succeed())
+		Expect(drControl.Spec.Action).To(Equal(ramenv1alpha1.ActionFailover))
+		Expect(drControl.Spec.FailoverCluster).To(Equal(testName + "-peer"))
+
+		By("Cleanups")
+		Expect(testClient.Delete(ctx, drControl)).To(Succeed())
+		Expect(testClient.Delete(ctx, ns)).To(Succeed())
+		Expect(testClient.Delete(ctx, mc)).To(Succeed())
+	})
+
+	It("should not failover dr controls with a dr policy not validated", func(ctx SpecContext) {
+		testName := "drpolicy-not-validated"
+
+		By("Create an unavailable ManagedCluster")
+		mc := createUnavailableCluster(ctx, testName)
+
+		By("Create a DRPlacementControl eligible for a failover")
+		drControl, ns := createDRControl(ctx, testName, mc.Name, nil, ramenv1alpha1.Deployed,
+			drCondition(ramenv1alpha1.ConditionPeerReady, metav1.ConditionTrue))
+
+		By("Update the DRPolicy as not validated")
+		drPolicy := &ramenv1alpha1.DRPolicy{}
+		Expect(testClient.Get(ctx, client.ObjectKey{Name: drControl.Spec.DRPolicyRef.Name}, drPolicy)).To(Succeed())
+		drPolicy.Status.Conditions[0].Status = metav1.ConditionFalse
+		Expect(testClient.Status().Update(ctx, drPolicy)).To(Succeed())
+
+		By("Reconcile for the MC with a recording controller")
+		recorder := record.NewFakeRecorder(10)
+		recordingController := &DRTriggerController{Client: testClient, Scheme: drtController.Scheme, Recorder: recorder}
+		_, err := recordingController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Verify the DRPC was not failed-over")
+		Expect(drAction(ctx, drControl)()).To(Equal(ramenv1alpha1.ActionRelocate))
+		Expect(recordedEvents(recorder)).To(ContainElement(ContainSubstring(ReasonFailoverSkippedDRPolicy)))
+
+		By("Cleanups")
+		Expect(testClient.Delete(ctx, drControl)).To(Succeed())
+		Expect(testClient.Delete(ctx, ns)).To(Succeed())
+		Expect(testClient.Delete(ctx, mc)).To(Succeed())
+	})
+})
@@ -0,0 +1,71 @@
+// Copyright (c) 2023 Red Hat, Inc.
+
+package controller
+
+import (
+	"context"
+	"slices"
+
+	"github.com/prometheus/client_golang/prometheus"
+	ramenv1alpha1 "github.com/ramendr/ramen/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var drPolicyDualFailureMetric = *prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "dr_policy_dual_failure",
+	Help: "Gauge set to 1 while both clusters of a DR Policy are unavailable, leaving its DR Applications with no DR path",
+}, []string{"dr_policy_name", "dr_cluster_name", "dr_peer_cluster_name"})
+
+// evaluateDualFailures is used for evaluating the DRPolicies of the ManagedCluster, returns the ones with both of their
+// clusters unavailable, mapped to the peer of the ManagedCluster. The dual failure gauge is derived from the current
+// state of both clusters, so a reconcile of either cluster sets or clears it, regardless of past reconciles.
+func (r *DRTriggerController) evaluateDualFailures(ctx context.Context, mc *clusterv1.ManagedCluster, targets map[string]*clusterv1.ManagedCluster) (map[string]string, error) {
+	logger := log.FromContext(ctx)
+
+	drPolicies := &ramenv1alpha1.DRPolicyList{}
+	if err := r.Client.List(ctx, drPolicies); err != nil {
+		return nil, err
+	}
+
+	available := meta.IsStatusConditionTrue(mc.Status.Conditions, clusterv1.ManagedClusterConditionAvailable)
+	failures := map[string]string{}
+	for _, drPolicy := range drPolicies.Items {
+		clusters := drPolicy.Spec.DRClusters
+		if len(clusters) != 2 || !slices.Contains(clusters, mc.Name) {
+			continue
+		}
+		peer := clusters[0]
+		if peer == mc.Name {
+			peer = clusters[1]
+		}
+
+		down := !available
+		if down {
+			target, err := r.getTargetCluster(ctx, peer, targets)
+			if err != nil {
+				return nil, err
+			}
+			down = isTargetDown(target)
+		}
+		if !down {
+			drPolicyDualFailureMetric.DeleteLabelValues(drPolicy.Name, clusters[0], clusters[1])
+			continue
+		}
+
+		failures[drPolicy.Name] = peer
+		drPolicyDualFailureMetric.WithLabelValues(drPolicy.Name, clusters[0], clusters[1]).Set(1)
+		logger.Error(nil, "dual failure, both dr policy clusters are unavailable", "dr_policy", drPolicy.Name,
+			"peer_cluster", peer)
+		r.Recorder.Eventf(mc, corev1.EventTypeWarning, ReasonDualFailure,
+			"Both DRPolicy %s clusters %s and %s are unavailable, there is no DR path", drPolicy.Name, mc.Name, peer)
+	}
+	return failures, nil
+}
+
+func init() {
+	metrics.Registry.MustRegister(drPolicyDualFailureMetric)
+}
@@ -0,0 +1,132 @@
+// Copyright (c) 2023 Red Hat, Inc.
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	ramenv1alpha1 "github.com/ramendr/ramen/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	rdrtriggerv1alpha1 "regional-dr-trigger-operator/api/v1alpha1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"time"
+)
+
+var _ = Context("Dual Failure", func() {
+	It("should report a dual failure until one of the dr policy clusters is available", func(ctx SpecContext) {
+		testName := "dual-failure"
+
+		By("Create an unavailable ManagedCluster")
+		mc := createUnavailableCluster(ctx, testName)
+
+		By("Create a DRPlacementControl eligible for a failover")
+		drControl, ns := createDRControl(ctx, testName, mc.Name, nil, ramenv1alpha1.Deployed,
+			drCondition(ramenv1alpha1.ConditionPeerReady, metav1.ConditionTrue))
+		drPolicyName := drControl.Spec.DRPolicyRef.Name
+
+		By("Update the peer MC as unavailable")
+		peer := &clusterv1.ManagedCluster{}
+		Expect(testClient.Get(ctx, client.ObjectKey{Name: testName + "-peer"}, peer)).To(Succeed())
+		meta.SetStatusCondition(&peer.Status.Conditions, metav1.Condition{
+			Type:   clusterv1.ManagedClusterConditionAvailable,
+			Status: metav1.ConditionFalse,
+			Reason: "MC_Not_Available",
+		})
+		Expect(testClient.Status().Update(ctx, peer)).To(Succeed())
+
+		By("Reconcile for the MC with a recording controller")
+		recorder := record.NewFakeRecorder(10)
+		recordingController := &DRTriggerController{Client: testClient, Scheme: drtController.Scheme, Recorder: recorder}
+		_, err := recordingController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Verify the DRPC was not failed-over and the dual failure is reported")
+		Expect(drAction(ctx, drControl)()).To(Equal(ramenv1alpha1.ActionRelocate))
+		Expect(recordedEvents(recorder)).To(ContainElement(ContainSubstring(ReasonDualFailure)))
+		Expect(testutil.ToFloat64(drPolicyDualFailureMetric.WithLabelValues(drPolicyName, mc.Name, peer.Name))).
+			To(Equal(float64(1)))
+
+		By("Update the peer MC as available")
+		meta.SetStatusCondition(&peer.Status.Conditions, metav1.Condition{
+			Type:   clusterv1.ManagedClusterConditionAvailable,
+			Status: metav1.ConditionTrue,
+			Reason: "MC_Available",
+		})
+		Expect(testClient.Status().Update(ctx, peer)).To(Succeed())
+
+		By("Reconcile for the peer MC with a new controller, not remembering the dual failure")
+		recordingController = &DRTriggerController{Client: testClient, Scheme: drtController.Scheme, Recorder: recorder}
+		_, err = recordingController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(peer)})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Verify the dual failure was cleared")
+		Expect(drPolicyDualFailureMetric.DeleteLabelValues(drPolicyName, mc.Name, peer.Name)).To(BeFalse())
+
+		By("Reconcile for the MC")
+		_, err = recordingController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Verify the DRPC was failed-over to the available peer")
+		Expect(drAction(ctx, drControl)()).To(Equal(ramenv1alpha1.ActionFailover))
+
+		By("Cleanups")
+		Expect(testClient.Delete(ctx, drControl)).To(Succeed())
+		Expect(testClient.Delete(ctx, ns)).To(Succeed())
+		Expect(testClient.Delete(ctx, mc)).To(Succeed())
+	})
+
+	It("should report a dual failure while the grace period postpones the failover", func(ctx SpecContext) {
+		testName := "dual-failure-grace"
+		selected := map[string]string{"test": testName}
+
+		By("Create an unavailable ManagedCluster")
+		mc := createUnavailableCluster(ctx, testName)
+
+		By("Create a DRPlacementControl eligible for a failover")
+		drControl, ns := createDRControl(ctx, testName, mc.Name, selected, ramenv1alpha1.Deployed,
+			drCondition(ramenv1alpha1.ConditionPeerReady, metav1.ConditionTrue))
+
+		By("Create a DRTriggerPolicy with a grace period")
+		policy := &rdrtriggerv1alpha1.DRTriggerPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: testName},
+			Spec: rdrtriggerv1alpha1.DRTriggerPolicySpec{
+				DRPlacementControlSelector: &metav1.LabelSelector{MatchLabels: selected},
+				UnavailableGracePeriod:     &metav1.Duration{Duration: time.Hour},
+			},
+		}
+		Expect(testClient.Create(ctx, policy)).To(Succeed())
+
+		By("Update the peer MC as unavailable")
+		peer := &clusterv1.ManagedCluster{}
+		Expect(testClient.Get(ctx, client.ObjectKey{Name: testName + "-peer"}, peer)).To(Succeed())
+		meta.SetStatusCondition(&peer.Status.Conditions, metav1.Condition{
+			Type:   clusterv1.ManagedClusterConditionAvailable,
+			Status: metav1.ConditionFalse,
+			Reason: "MC_Not_Available",
+		})
+		Expect(testClient.Status().Update(ctx, peer)).To(Succeed())
+
+		By("Reconcile for the MC with a recording controller")
+		recorder := record.NewFakeRecorder(10)
+		recordingController := &DRTriggerController{Client: testClient, Scheme: drtController.Scheme, Recorder: recorder}
+		_, err := recordingController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Verify the dual failure was reported within the grace period")
+		events := recordedEvents(recorder)
+		Expect(events).To(ContainElement(
+			ContainSubstring(ReasonDualFailure + " Both DRPolicy " + drControl.Spec.DRPolicyRef.Name)))
+		Expect(events).NotTo(ContainElement(ContainSubstring(ReasonFailoverSkippedTargetUnavailable)))
+
+		By("Cleanups")
+		Expect(testClient.Delete(ctx, policy)).To(Succeed())
+		Expect(testClient.Delete(ctx, drControl)).To(Succeed())
+		Expect(testClient.Delete(ctx, ns)).To(Succeed())
+		Expect(testClient.Delete(ctx, mc)).To(Succeed())
+	})
+})
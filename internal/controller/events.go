@@ -0,0 +1,94 @@
+// Copyright (c) 2023 Red Hat, Inc.
+
+package controller
+
+import (
+	"fmt"
+
+	ramenv1alpha1 "github.com/ramendr/ramen/api/v1alpha1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+)
+
+// Event reasons recorded by the controller, these are stable and can be used for filtering events.
+const (
+	// ReasonFailoverTriggered is used when a DRPlacementControl was patched for a failover
+	ReasonFailoverTriggered = "FailoverTriggered"
+	// ReasonFailoverSkippedPeerNotReady is used when a DRPlacementControl required condition, i.e. PeerReady, is not met
+	ReasonFailoverSkippedPeerNotReady = "FailoverSkippedPeerNotReady"
+	// ReasonFailoverSkippedPhase is used when a DRPlacementControl is not in a phase suitable for a failover
+	ReasonFailoverSkippedPhase = "FailoverSkippedPhase"
+	// ReasonFailoverSkippedOptOut is used when a DRPlacementControl or its Namespace opted out of automatic failover
+	ReasonFailoverSkippedOptOut = "FailoverSkippedOptOut"
+	// ReasonFailoverSkippedDRPolicy is used when the DRPlacementControl failover cluster can not be resolved from its
+	// DRPolicy, i.e. the DRPolicy is not validated
+	ReasonFailoverSkippedDRPolicy = "FailoverSkippedDRPolicy"
+	// ReasonFailoverSkippedTargetUnavailable is used when the failover cluster is not joined or not available
+	ReasonFailoverSkippedTargetUnavailable = "FailoverSkippedTargetUnavailable"
+	// ReasonFailoverSkippedCooldown is used when a DRPlacementControl was acted on within the failover cooldown
+	ReasonFailoverSkippedCooldown = "FailoverSkippedCooldown"
+	// ReasonDualFailure is used when both the preferred cluster and its DRPolicy peer are unavailable
+	ReasonDualFailure = "DualFailure"
+	// ReasonInFlightWaiting is used when an in-flight DRPlacementControl operation is left as is, waiting for the
+	// unavailable ManagedCluster to recover
+	ReasonInFlightWaiting = "InFlightWaiting"
+	// ReasonInFlightConverted is used when an in-flight DRPlacementControl operation was converted to a failover
+	ReasonInFlightConverted = "InFlightConverted"
+	// ReasonInFlightEscalated is used when an in-flight DRPlacementControl operation with an unavailable ManagedCluster
+	// was escalated for a decision by an administrator
+	ReasonInFlightEscalated = "InFlightEscalated"
+	// ReasonBreakGlassFailover is used when a failover was forced using break-glass, risking data loss
+	ReasonBreakGlassFailover = "BreakGlassFailover"
+	// ReasonBreakGlassIgnored is used when a requested break-glass is not active, i.e. it expired
+	ReasonBreakGlassIgnored = "BreakGlassIgnored"
+	// ReasonFailoverAlreadyInitiated is used when a DRPlacementControl action is already set to failover
+	ReasonFailoverAlreadyInitiated = "FailoverAlreadyInitiated"
+	// ReasonPatchFailed is used when patching a DRPlacementControl for a failover failed
+	ReasonPatchFailed = "PatchFailed"
+	// ReasonFailoverHeld is used when a failover is held until higher priority DRPlacementControls are released
+	ReasonFailoverHeld = "FailoverHeld"
+	// ReasonFailoverQueued is used when a failover was queued for exceeding the failover limits
+	ReasonFailoverQueued = "FailoverQueued"
+	// ReasonFailoverDryRun is used when a failover would have been initiated if not for dry-run mode
+	ReasonFailoverDryRun = "FailoverDryRun"
+	// ReasonFailoverPaused is used when a failover would have been initiated if the operator was not paused
+	ReasonFailoverPaused = "FailoverPaused"
+	// ReasonFailoverSilenced is used when a failover would have been initiated if not silenced by a FailoverSilence
+	ReasonFailoverSilenced = "FailoverSilenced"
+	// ReasonFailbackTriggered is used when a DRPlacementControl was patched for a failback to its recovered cluster
+	ReasonFailbackTriggered = "FailbackTriggered"
+	// ReasonFailbackHeld is used when a failback is held, i.e. until a maintenance window or the peer is ready
+	ReasonFailbackHeld = "FailbackHeld"
+	// ReasonFailbackDryRun is used when a failback would have been initiated if not for dry-run mode
+	ReasonFailbackDryRun = "FailbackDryRun"
+	// ReasonFailbackPaused is used when a failback would have been initiated if the operator was not paused
+	ReasonFailbackPaused = "FailbackPaused"
+	// ReasonCircuitBreakerOpen is used when automatic failovers are paused by the circuit breaker on a mass outage
+	ReasonCircuitBreakerOpen = "CircuitBreakerOpen"
+	// ReasonCircuitBreakerClosed is used when the circuit breaker closed, resuming automatic failovers
+	ReasonCircuitBreakerClosed = "CircuitBreakerClosed"
+	// ReasonClusterFlapping is used when a ManagedCluster availability transitioned too many times within a window
+	ReasonClusterFlapping = "ClusterFlapping"
+	// ReasonClusterStable is used when a flapping ManagedCluster availability did not transition for a whole window
+	ReasonClusterStable = "ClusterStable"
+	// ReasonClusterLeaseStale is used when a ManagedCluster is marked unavailable for not renewing its lease
+	ReasonClusterLeaseStale = "ClusterLeaseStale"
+	// ReasonFailoverUnconfirmed is used when a failover is held for the outage of a ManagedCluster not being confirmed,
+	// i.e. its lease is still renewed
+	ReasonFailoverUnconfirmed = "FailoverUnconfirmed"
+	// ReasonPrometheusQueryFailed is used when a failover is held for a Prometheus query confirming the outage of a
+	// ManagedCluster failing
+	ReasonPrometheusQueryFailed = "PrometheusQueryFailed"
+	// ReasonFailoverAwaitingApproval is used when the failover of a flapping ManagedCluster, or of a ManagedCluster
+	// requiring approval, is awaiting approval
+	ReasonFailoverAwaitingApproval = "FailoverAwaitingApproval"
+	// ReasonFailoverPlanCreated is used when a FailoverPlan was created for a ManagedCluster requiring approval
+	ReasonFailoverPlanCreated = "FailoverPlanCreated"
+)
+
+// recordEvent is used for recording an event on both the DRPlacementControl and the ManagedCluster. App teams can
+// describe their DRPlacementControl, while cluster admins can describe the ManagedCluster for all of its decisions.
+func (r *DRTriggerController) recordEvent(mc *clusterv1.ManagedCluster, control *ramenv1alpha1.DRPlacementControl, eventType, reason, messageFmt string, args ...interface{}) {
+	message := fmt.Sprintf(messageFmt, args...)
+	r.Recorder.Event(control, eventType, reason, message)
+	r.Recorder.Eventf(mc, eventType, reason, "DRPlacementControl %s/%s: %s", control.Namespace, control.Name, message)
+}
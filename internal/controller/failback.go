@@ -0,0 +1,231 @@
+// Copyright (c) 2023 Red Hat, Inc.
+
+package controller
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/prometheus/client_golang/prometheus"
+	ramenv1alpha1 "github.com/ramendr/ramen/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	rdrtriggerv1alpha1 "regional-dr-trigger-operator/api/v1alpha1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var drApplicationFailbackMetric = *prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "dr_application_failback_count",
+	Help: "Counter for DR Applications failback initiated by the Regional DR Trigger Operator",
+}, []string{"dr_cluster_name", "dr_control_name", "dr_application_name"})
+
+// failbackOptIn is a utility function returning true if the DRPlacementControl opted in for automatic failback
+func failbackOptIn(control ramenv1alpha1.DRPlacementControl) bool {
+	return control.Annotations[rdrtriggerv1alpha1.AutoFailbackAnnotation] == "true"
+}
+
+// availableFor is a utility function returning the duration a ManagedCluster is available for, measured from the last
+// transition time of its Available condition
+func availableFor(mc *clusterv1.ManagedCluster) time.Duration {
+	return conditionTrueFor(mc.Status.Conditions, clusterv1.ManagedClusterConditionAvailable)
+}
+
+// conditionTrueFor is a utility function returning the duration a condition is true for, measured from its last
+// transition time, or zero if the condition is missing or not true
+func conditionTrueFor(conditions []metav1.Condition, conditionType string) time.Duration {
+	condition := meta.FindStatusCondition(conditions, conditionType)
+	if condition == nil || condition.Status != metav1.ConditionTrue {
+		return 0
+	}
+	return time.Since(condition.LastTransitionTime.Time)
+}
+
+// reconcileFailbacks is used for relocating the DRPlacementControls opted in for automatic failback back to their
+// preferred ManagedCluster, once it is available, and they are PeerReady, for the failback hold period. Failbacks are
+// only initiated within the policy maintenance windows, and higher priority DRPlacementControls are relocated first,
+// holding the lower ones until Relocated. While paused, failbacks are only reported.
+func (r *DRTriggerController) reconcileFailbacks(ctx context.Context, mc *clusterv1.ManagedCluster, paused bool) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	drControls := &ramenv1alpha1.DRPlacementControlList{}
+	if err := r.Client.List(ctx, drControls); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	policies, err := r.listPolicies(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// higher priority dr controls are failed back first
+	sortByPriority(ctx, drControls.Items)
+	gate := &priorityGate{}
+
+	var errs *multierror.Error
+	var requeueAfter time.Duration
+	for _, drControl := range drControls.Items {
+		// dr controls preferring current managed cluster, opted in for automatic failback
+		if drControl.Spec.PreferredCluster != mc.Name || !failbackOptIn(drControl) {
+			continue
+		}
+		priority := failoverPriority(ctx, drControl)
+
+		// relocating dr controls hold the lower priorities until relocated
+		if drControl.Spec.Action == ramenv1alpha1.ActionRelocate {
+			if drControl.Status.Phase != ramenv1alpha1.Relocated {
+				gate.pending(priority)
+			}
+			continue
+		}
+
+		// dr controls failed over away from current managed cluster
+		if !failedOver(drControl) || drControl.Spec.FailoverCluster == mc.Name {
+			continue
+		}
+
+		policy := selectPolicy(ctx, policies, mc, drControl)
+		if !policy.IsEnabled() {
+			continue
+		}
+
+		// managed cluster available for the failback hold period
+		if remaining := r.FailbackHoldPeriod - availableFor(mc); remaining > 0 {
+			logger.Info("managed cluster available for less than the failback hold period, postponing failback",
+				"drpc_name", drControl.Name, "drpc_ns", drControl.Namespace, "remaining", remaining.String())
+			requeueAfter = minRequeue(requeueAfter, remaining)
+			gate.pending(priority)
+			continue
+		}
+
+		// dr control peer is ready for a relocate
+		if !meta.IsStatusConditionTrue(drControl.Status.Conditions, ramenv1alpha1.ConditionPeerReady) {
+			logger.Info("dr control peer not ready, postponing failback", "drpc_name",
+				drControl.Name, "drpc_ns", drControl.Namespace)
+			r.recordEvent(mc, &drControl, corev1.EventTypeNormal, ReasonFailbackHeld,
+				"Failback held until condition %s is met", ramenv1alpha1.ConditionPeerReady)
+			gate.pending(priority)
+			continue
+		}
+
+		// dr control peer ready for the failback hold period
+		if remaining := r.FailbackHoldPeriod - conditionTrueFor(drControl.Status.Conditions, ramenv1alpha1.ConditionPeerReady); remaining > 0 {
+			logger.Info("dr control peer ready for less than the failback hold period, postponing failback",
+				"drpc_name", drControl.Name, "drpc_ns", drControl.Namespace, "remaining", remaining.String())
+			requeueAfter = minRequeue(requeueAfter, remaining)
+			gate.pending(priority)
+			continue
+		}
+
+		// dr control not acted on within the cooldown
+		if remaining := r.cooldownRemaining(ctx, drControl); remaining > 0 {
+			logger.Info("dr control acted on within the cooldown, postponing failback", "drpc_name",
+				drControl.Name, "drpc_ns", drControl.Namespace, "remaining", remaining.String())
+			requeueAfter = minRequeue(requeueAfter, remaining)
+			gate.pending(priority)
+			continue
+		}
+
+		// within a maintenance window of the policy
+		if open, next := maintenanceWindow(policy.Spec.FailbackWindows, time.Now()); !open {
+			logger.Info("outside of the policy maintenance windows, postponing failback", "drpc_name",
+				drControl.Name, "drpc_ns", drControl.Namespace, "policy", policyName(policy), "next", next.String())
+			r.recordEvent(mc, &drControl, corev1.EventTypeNormal, ReasonFailbackHeld,
+				"Failback held until the next maintenance window in %s", next.Round(time.Minute))
+			if next > 0 {
+				requeueAfter = minRequeue(requeueAfter, next)
+			}
+			gate.pending(priority)
+			continue
+		}
+
+		// dry-run mode, only report the failback decision
+		if r.DryRun || policy.Spec.DryRun {
+			logger.Info("dry-run, would have patched dr control for a failback", "drpc_name", drControl.Name,
+				"drpc_ns", drControl.Namespace, "policy", policyName(policy))
+			r.recordEvent(mc, &drControl, corev1.EventTypeNormal, ReasonFailbackDryRun,
+				"Dry-run, would have initiated a failback to the recovered managed cluster %s", mc.Name)
+			continue
+		}
+
+		// operator paused, only report the failback decision
+		if paused {
+			logger.Info("operator paused, would have patched dr control for a failback", "drpc_name", drControl.Name,
+				"drpc_ns", drControl.Namespace)
+			r.recordEvent(mc, &drControl, corev1.EventTypeNormal, ReasonFailbackPaused,
+				"Operator paused, would have initiated a failback to the recovered managed cluster %s", mc.Name)
+			continue
+		}
+
+		// higher priority dr controls relocated
+		if gate.holds(priority) {
+			logger.Info("higher priority dr controls pending, holding dr control failback", "drpc_name",
+				drControl.Name, "drpc_ns", drControl.Namespace, "priority", priority)
+			r.recordEvent(mc, &drControl, corev1.EventTypeNormal, ReasonFailbackHeld,
+				"Failback held until higher priority DRPlacementControls are %s", ramenv1alpha1.Relocated)
+			requeueAfter = minRequeue(requeueAfter, priorityRequeueInterval)
+			continue
+		}
+
+		// patch dr control and initiate a relocate process back to the preferred cluster
+		gate.pending(priority)
+		err := r.patchDRPlacementControl(ctx, drControl, ramenv1alpha1.ActionRelocate, drControl.Spec.FailoverCluster)
+		if err != nil {
+			errs = multierror.Append(err, errs)
+			r.recordEvent(mc, &drControl, corev1.EventTypeWarning, ReasonPatchFailed,
+				"Failed patching for a failback, managed cluster %s recovered: %v", mc.Name, err)
+			continue
+		}
+		logger.Info("successfully patched dr control for a failback", "drpc_name", drControl.Name,
+			"drpc_ns", drControl.Namespace)
+		r.recordEvent(mc, &drControl, corev1.EventTypeNormal, ReasonFailbackTriggered,
+			"Failback initiated, relocating back to the recovered managed cluster %s", mc.Name)
+		drApplicationFailbackMetric.WithLabelValues(mc.Name, drControl.Name, drControl.Namespace).Inc()
+	}
+
+	return ctrl.Result{RequeueAfter: requeueAfter}, errs.ErrorOrNil()
+}
+
+// maintenanceWindow is a utility function returning true if the time is within any of the maintenance windows, or the
+// duration until the next window starts. No windows allow any time.
+func maintenanceWindow(windows []rdrtriggerv1alpha1.MaintenanceWindow, now time.Time) (bool, time.Duration) {
+	if len(windows) == 0 {
+		return true, 0
+	}
+
+	now = now.UTC()
+	var next time.Duration
+	for _, window := range windows {
+		start, err := time.Parse("15:04", window.Start)
+		if err != nil {
+			continue
+		}
+		// windows are up to a week, so a window open now started within the last week
+		for day := -7; day <= 7; day++ {
+			date := now.AddDate(0, 0, day)
+			if len(window.Days) > 0 && !slices.ContainsFunc(window.Days, func(d rdrtriggerv1alpha1.Weekday) bool {
+				return strings.EqualFold(string(d), date.Weekday().String())
+			}) {
+				continue
+			}
+			opens := time.Date(date.Year(), date.Month(), date.Day(), start.Hour(), start.Minute(), 0, 0, time.UTC)
+			if !now.Before(opens) && now.Before(opens.Add(window.Duration.Duration)) {
+				return true, 0
+			}
+			if until := opens.Sub(now); until > 0 && (next == 0 || until < next) {
+				next = until
+			}
+		}
+	}
+	return false, next
+}
+
+func init() {
+	metrics.Registry.MustRegister(drApplicationFailbackMetric)
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2023 Red Hat, Inc.
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	ramenv1alpha1 "github.com/ramendr/ramen/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rdrtriggerv1alpha1 "regional-dr-trigger-operator/api/v1alpha1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"time"
+)
+
+var _ = Context("Automatic Failback", func() {
+	It("should relocate dr controls opted in back to their recovered cluster after the hold period", func(ctx SpecContext) {
+		testName := "failback"
+
+		By("Create an available ManagedCluster")
+		mc := createAvailableCluster(ctx, testName)
+
+		By("Create two DRPlacementControls failed over from the MC to their peer, one opted in for failback")
+		optInDr, optInNs := createDRControl(ctx, testName+"-in", mc.Name, nil, ramenv1alpha1.FailedOver,
+			drCondition(ramenv1alpha1.ConditionPeerReady, metav1.ConditionTrue))
+		noOptInDr, noOptInNs := createDRControl(ctx, testName+"-none", mc.Name, nil, ramenv1alpha1.FailedOver,
+			drCondition(ramenv1alpha1.ConditionPeerReady, metav1.ConditionTrue))
+		peers := map[*ramenv1alpha1.DRPlacementControl]string{
+			optInDr: testName + "-in-peer", noOptInDr: testName + "-none-peer"}
+		for drControl, peer := range peers {
+			drControl.Spec.PreferredCluster = mc.Name
+			drControl.Spec.Action = ramenv1alpha1.ActionFailover
+			drControl.Spec.FailoverCluster = peer
+			Expect(testClient.Update(ctx, drControl)).To(Succeed())
+		}
+		optInDr.Annotations = map[string]string{rdrtriggerv1alpha1.AutoFailbackAnnotation: "true"}
+		Expect(testClient.Update(ctx, optInDr)).To(Succeed())
+
+		By("Reconcile for the MC with an hour hold period")
+		holdController := &DRTriggerController{Client: testClient, Scheme: drtController.Scheme,
+			Recorder: drtController.Recorder, FailbackHoldPeriod: time.Hour}
+		res, err := holdController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Verify the DRPC was not failed-back and requeued for the end of the hold period")
+		Expect(drAction(ctx, optInDr)()).To(Equal(ramenv1alpha1.ActionFailover))
+		Expect(res.RequeueAfter).To(BeNumerically(">", 59*time.Minute))
+
+		By("Reconcile for the MC with no hold period")
+		_, err = drtController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Verify only the opted in DRPC was failed-back")
+		Expect(drAction(ctx, optInDr)()).To(Equal(ramenv1alpha1.ActionRelocate))
+		Expect(drAction(ctx, noOptInDr)()).To(Equal(ramenv1alpha1.ActionFailover))
+
+		By("Cleanups")
+		Expect(testClient.Delete(ctx, noOptInDr)).To(Succeed())
+		Expect(testClient.Delete(ctx, noOptInNs)).To(Succeed())
+		Expect(testClient.Delete(ctx, optInDr)).To(Succeed())
+		Expect(testClient.Delete(ctx, optInNs)).To(Succeed())
+	})
+
+	It("should hold failbacks until the dr controls are PeerReady for the hold period", func(ctx SpecContext) {
+		testName := "failback-peer-ready"
+
+		By("Create a ManagedCluster available for longer than the hold period")
+		mc := createAvailableCluster(ctx, testName)
+		for i := range mc.Status.Conditions {
+			mc.Status.Conditions[i].LastTransitionTime = metav1.NewTime(time.Now().Add(-2 * time.Hour))
+		}
+		Expect(testClient.Status().Update(ctx, mc)).To(Succeed())
+
+		By("Create a DRPlacementControl opted in for failback, failed over to its peer, recently PeerReady")
+		drControl, drNs := createDRControl(ctx, testName, mc.Name, nil, ramenv1alpha1.FailedOver,
+			drCondition(ramenv1alpha1.ConditionPeerReady, metav1.ConditionTrue))
+		drControl.Annotations = map[string]string{rdrtriggerv1alpha1.AutoFailbackAnnotation: "true"}
+		drControl.Spec.PreferredCluster = mc.Name
+		drControl.Spec.Action = ramenv1alpha1.ActionFailover
+		drControl.Spec.FailoverCluster = testName + "-peer"
+		Expect(testClient.Update(ctx, drControl)).To(Succeed())
+
+		By("Reconcile for the MC with an hour hold period")
+		holdController := &DRTriggerController{Client: testClient, Scheme: drtController.Scheme,
+			Recorder: drtController.Recorder, FailbackHoldPeriod: time.Hour}
+		res, err := holdController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Verify the DRPC was not failed-back and requeued for the end of the PeerReady hold period")
+		Expect(drAction(ctx, drControl)()).To(Equal(ramenv1alpha1.ActionFailover))
+		Expect(res.RequeueAfter).To(BeNumerically(">", 59*time.Minute))
+
+		By("Set the DRPC PeerReady for longer than the hold period")
+		Expect(testClient.Get(ctx, client.ObjectKeyFromObject(drControl), drControl)).To(Succeed())
+		for i := range drControl.Status.Conditions {
+			drControl.Status.Conditions[i].LastTransitionTime = metav1.NewTime(time.Now().Add(-2 * time.Hour))
+		}
+		Expect(testClient.Status().Update(ctx, drControl)).To(Succeed())
+
+		By("Reconcile for the MC with an hour hold period")
+		_, err = holdController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Verify the DRPC was failed-back")
+		Expect(drAction(ctx, drControl)()).To(Equal(ramenv1alpha1.ActionRelocate))
+
+		By("Cleanups")
+		Expect(testClient.Delete(ctx, drControl)).To(Succeed())
+		Expect(testClient.Delete(ctx, drNs)).To(Succeed())
+	})
+
+	It("should only allow failbacks within the maintenance windows", func() {
+		windows := []rdrtriggerv1alpha1.MaintenanceWindow{
+			{Days: []rdrtriggerv1alpha1.Weekday{"Monday"}, Start: "02:00", Duration: metav1.Duration{Duration: 2 * time.Hour}},
+			{Days: []rdrtriggerv1alpha1.Weekday{"Friday"}, Start: "23:00", Duration: metav1.Duration{Duration: 4 * time.Hour}},
+		}
+		monday := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+		By("Verify no windows allow any time")
+		open, _ := maintenanceWindow(nil, monday)
+		Expect(open).To(BeTrue())
+
+		By("Verify a time within a window is allowed")
+		open, _ = maintenanceWindow(windows, monday.Add(3*time.Hour))
+		Expect(open).To(BeTrue())
+
+		By("Verify a window spanning midnight is allowed on the next day")
+		open, _ = maintenanceWindow(windows, monday.AddDate(0, 0, 5).Add(time.Hour))
+		Expect(open).To(BeTrue())
+
+		By("Verify a time outside the windows is held until the next window")
+		open, next := maintenanceWindow(windows, monday.Add(5*time.Hour))
+		Expect(open).To(BeFalse())
+		Expect(next).To(Equal(4*24*time.Hour + 18*time.Hour))
+	})
+})
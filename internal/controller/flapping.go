@@ -0,0 +1,125 @@
+// Copyright (c) 2023 Red Hat, Inc.
+
+package controller
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	ramenv1alpha1 "github.com/ramendr/ramen/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	rdrtriggerv1alpha1 "regional-dr-trigger-operator/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// defaultFlappingGracePeriod is the grace period required for flapping clusters when the policy does not set one
+const defaultFlappingGracePeriod = 15 * time.Minute
+
+var drClusterFlappingMetric = *prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "dr_cluster_flapping",
+	Help: "Gauge set to 1 while a Managed Cluster is flapping, its availability transitioning too many times within the flap detection window",
+}, []string{"dr_cluster_name"})
+
+// FlapDetection marks ManagedClusters as flapping when their Available condition transitions too many times within a
+// window. A flapping cluster is marked stable again only after a whole window without transitions.
+type FlapDetection struct {
+	// Threshold is the number of transitions within the window marking a cluster as flapping, 0 disables the detection
+	Threshold int
+	// Window is the duration transitions are counted for
+	Window time.Duration
+}
+
+// enabled returns true if a threshold is set for a window
+func (d FlapDetection) enabled() bool {
+	return d.Threshold > 0 && d.Window > 0
+}
+
+// flapHistory is the recent Available condition transitions of a ManagedCluster
+type flapHistory struct {
+	last        time.Time
+	transitions []time.Time
+	flapping    bool
+}
+
+// flapTracker tracks the Available condition transitions per ManagedCluster. The zero value is ready for use.
+type flapTracker struct {
+	mu       sync.Mutex
+	clusters map[string]*flapHistory
+}
+
+// observe is used for recording the current Available condition transition of the ManagedCluster. Returns true if the
+// cluster is flapping, and true if its flapping state changed.
+func (t *flapTracker) observe(mc *clusterv1.ManagedCluster, detection FlapDetection) (bool, bool) {
+	if !detection.enabled() {
+		return false, false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.clusters == nil {
+		t.clusters = map[string]*flapHistory{}
+	}
+	history, found := t.clusters[mc.Name]
+	if !found {
+		history = &flapHistory{}
+		t.clusters[mc.Name] = history
+	}
+
+	condition := meta.FindStatusCondition(mc.Status.Conditions, clusterv1.ManagedClusterConditionAvailable)
+	if condition != nil && !condition.LastTransitionTime.Time.Equal(history.last) {
+		history.last = condition.LastTransitionTime.Time
+		history.transitions = append(history.transitions, history.last)
+	}
+
+	since := time.Now().Add(-detection.Window)
+	recent := history.transitions[:0]
+	for _, at := range history.transitions {
+		if at.After(since) {
+			recent = append(recent, at)
+		}
+	}
+	history.transitions = recent
+
+	// hysteresis, flapping starts at the threshold, but only stops after a whole window without transitions
+	wasFlapping := history.flapping
+	if len(recent) >= detection.Threshold {
+		history.flapping = true
+	} else if len(recent) == 0 {
+		history.flapping = false
+	}
+
+	if history.flapping == wasFlapping {
+		return history.flapping, false
+	}
+	if history.flapping {
+		drClusterFlappingMetric.WithLabelValues(mc.Name).Set(1)
+	} else {
+		drClusterFlappingMetric.DeleteLabelValues(mc.Name)
+	}
+	return history.flapping, true
+}
+
+// flappingGracePeriod is a utility function returning the duration a flapping ManagedCluster is required to be
+// unavailable before failing over the DRPlacementControls selected by the policy
+func flappingGracePeriod(policy *rdrtriggerv1alpha1.DRTriggerPolicy) time.Duration {
+	if policy.Spec.Flapping != nil && policy.Spec.Flapping.UnavailableGracePeriod != nil {
+		return policy.Spec.Flapping.UnavailableGracePeriod.Duration
+	}
+	return defaultFlappingGracePeriod
+}
+
+// awaitingApproval is a utility function returning true if the policy requires approving the failover of flapping
+// ManagedClusters, and the DRPlacementControl was not approved using the failover-approved annotation
+func awaitingApproval(policy *rdrtriggerv1alpha1.DRTriggerPolicy, control ramenv1alpha1.DRPlacementControl) bool {
+	if policy.Spec.Flapping == nil || !policy.Spec.Flapping.RequireApproval {
+		return false
+	}
+	return control.Annotations[rdrtriggerv1alpha1.FailoverApprovedAnnotation] != "true"
+}
+
+func init() {
+	metrics.Registry.MustRegister(drClusterFlappingMetric)
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2023 Red Hat, Inc.
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	ramenv1alpha1 "github.com/ramendr/ramen/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	rdrtriggerv1alpha1 "regional-dr-trigger-operator/api/v1alpha1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"time"
+)
+
+var _ = Context("Flap Detection", func() {
+	It("should require approval for failing over dr controls of a flapping cluster", func(ctx SpecContext) {
+		testName := "flapping"
+		selected := map[string]string{"test": testName}
+
+		By("Create a ManagedCluster unavailable since three minutes")
+		mc := createUnavailableClusterSince(ctx, testName, time.Now().Add(-3*time.Minute))
+
+		By("Create a DRPlacementControl eligible for a failover")
+		drControl, ns := createDRControl(ctx, testName, mc.Name, selected, ramenv1alpha1.Deployed,
+			drCondition(ramenv1alpha1.ConditionPeerReady, metav1.ConditionTrue))
+
+		By("Create a DRTriggerPolicy requiring approval for flapping clusters")
+		policy := &rdrtriggerv1alpha1.DRTriggerPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: testName},
+			Spec: rdrtriggerv1alpha1.DRTriggerPolicySpec{
+				DRPlacementControlSelector: &metav1.LabelSelector{MatchLabels: selected},
+				Flapping: &rdrtriggerv1alpha1.FlappingRules{
+					UnavailableGracePeriod: &metav1.Duration{},
+					RequireApproval:        true,
+				},
+			},
+		}
+		Expect(testClient.Create(ctx, policy)).To(Succeed())
+
+		By("Reconcile for the MC transitioning to available and back to unavailable")
+		recorder := record.NewFakeRecorder(20)
+		flapController := &DRTriggerController{Client: testClient, Scheme: drtController.Scheme, Recorder: recorder,
+			Flapping: FlapDetection{Threshold: 3, Window: time.Hour}}
+		_, err := flapController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)})
+		Expect(err).NotTo(HaveOccurred())
+		for n, status := range []metav1.ConditionStatus{metav1.ConditionTrue, metav1.ConditionFalse} {
+			meta.SetStatusCondition(&mc.Status.Conditions, metav1.Condition{
+				Type:               clusterv1.ManagedClusterConditionAvailable,
+				Status:             status,
+				Reason:             "MC_Flapping",
+				LastTransitionTime: metav1.NewTime(time.Now().Add(time.Duration(n-2) * time.Minute)),
+			})
+			Expect(testClient.Status().Update(ctx, mc)).To(Succeed())
+			_, err = flapController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		By("Verify the MC is flapping and the DRPC failover is awaiting approval")
+		Expect(drAction(ctx, drControl)()).To(Equal(ramenv1alpha1.ActionRelocate))
+		events := recordedEvents(recorder)
+		Expect(events).To(ContainElement(ContainSubstring(ReasonClusterFlapping)))
+		Expect(events).To(ContainElement(ContainSubstring(ReasonFailoverAwaitingApproval)))
+
+		By("Approve the DRPC failover")
+		Expect(testClient.Get(ctx, client.ObjectKeyFromObject(drControl), drControl)).To(Succeed())
+		drControl.Annotations = map[string]string{rdrtriggerv1alpha1.FailoverApprovedAnnotation: "true"}
+		Expect(testClient.Update(ctx, drControl)).To(Succeed())
+
+		By("Reconcile for the MC")
+		_, err = flapController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Verify the DRPC was failed-over and its approval consumed")
+		Expect(drAction(ctx, drControl)()).To(Equal(ramenv1alpha1.ActionFailover))
+		Expect(testClient.Get(ctx, client.ObjectKeyFromObject(drControl), drControl)).To(Succeed())
+		Expect(drControl.Annotations).NotTo(HaveKey(rdrtriggerv1alpha1.FailoverApprovedAnnotation))
+		Expect(drControl.Annotations).To(HaveKey(rdrtriggerv1alpha1.LastAutomatedActionAnnotation))
+
+		By("Cleanups")
+		Expect(testClient.Delete(ctx, policy)).To(Succeed())
+		Expect(testClient.Delete(ctx, drControl)).To(Succeed())
+		Expect(testClient.Delete(ctx, ns)).To(Succeed())
+		Expect(testClient.Delete(ctx, mc)).To(Succeed())
+	})
+})
@@ -0,0 +1,45 @@
+// Copyright (c) 2023 Red Hat, Inc.
+
+package controller
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	ramenv1alpha1 "github.com/ramendr/ramen/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var drApplicationInFlightEscalatedMetric = *prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "dr_application_inflight_escalated_count",
+	Help: "Counter for DR Applications in-flight operations with an unavailable cluster escalated by the Regional DR Trigger Operator",
+}, []string{"dr_cluster_name", "dr_control_name", "dr_application_name"})
+
+// inFlight is a utility function returning true if the DRPlacementControl is in the middle of a relocate, Relocating,
+// or Initiating while its application still runs on the previous cluster. A new DRPlacementControl is Initiating as
+// well, with no decision or with a decision already on its preferred cluster, and is left to the phase rules.
+func inFlight(control ramenv1alpha1.DRPlacementControl) bool {
+	if control.Spec.Action != ramenv1alpha1.ActionRelocate {
+		return false
+	}
+	switch control.Status.Phase {
+	case ramenv1alpha1.Relocating:
+		return true
+	case ramenv1alpha1.Initiating:
+		decision := control.Status.PreferredDecision.ClusterName
+		return decision != "" && decision != control.Spec.PreferredCluster
+	default:
+		return false
+	}
+}
+
+// involvesCluster is a utility function returning true if the DRPlacementControl application runs on the cluster, or
+// is in the middle of an operation moving it to the cluster
+func involvesCluster(control ramenv1alpha1.DRPlacementControl, cluster string) bool {
+	if currentCluster(control) == cluster {
+		return true
+	}
+	return inFlight(control) && control.Spec.PreferredCluster == cluster
+}
+
+func init() {
+	metrics.Registry.MustRegister(drApplicationInFlightEscalatedMetric)
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2023 Red Hat, Inc.
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	ramenv1alpha1 "github.com/ramendr/ramen/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	rdrtriggerv1alpha1 "regional-dr-trigger-operator/api/v1alpha1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Context("In-Flight Operations", func() {
+	It("should handle dr controls relocating to an unavailable cluster using the policy strategy", func(ctx SpecContext) {
+		testName := "inflight"
+		selected := map[string]string{"test": testName}
+
+		By("Create an unavailable ManagedCluster")
+		mc := createUnavailableCluster(ctx, testName)
+
+		By("Create two DRPlacementControls relocating from their available peer to the MC")
+		waitDr, waitNs := createDRControl(ctx, testName+"-wait", mc.Name, nil, ramenv1alpha1.Relocating,
+			drCondition(ramenv1alpha1.ConditionPeerReady, metav1.ConditionTrue))
+		convertDr, convertNs := createDRControl(ctx, testName+"-convert", mc.Name, selected, ramenv1alpha1.Relocating,
+			drCondition(ramenv1alpha1.ConditionPeerReady, metav1.ConditionTrue))
+		peers := map[*ramenv1alpha1.DRPlacementControl]string{
+			waitDr: testName + "-wait-peer", convertDr: testName + "-convert-peer"}
+		for drControl, peer := range peers {
+			drControl.Status.PreferredDecision.ClusterName = peer
+			Expect(testClient.Status().Update(ctx, drControl)).To(Succeed())
+			drControl.Spec.PreferredCluster = mc.Name
+			Expect(testClient.Update(ctx, drControl)).To(Succeed())
+		}
+
+		By("Create a DRTriggerPolicy converting in-flight operations to a failover")
+		policy := &rdrtriggerv1alpha1.DRTriggerPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: testName},
+			Spec: rdrtriggerv1alpha1.DRTriggerPolicySpec{
+				DRPlacementControlSelector: &metav1.LabelSelector{MatchLabels: selected},
+				InFlightStrategy:           rdrtriggerv1alpha1.InFlightFailover,
+			},
+		}
+		Expect(testClient.Create(ctx, policy)).To(Succeed())
+
+		By("Reconcile for the MC with a recording controller")
+		recorder := record.NewFakeRecorder(20)
+		recordingController := &DRTriggerController{Client: testClient, Scheme: drtController.Scheme, Recorder: recorder}
+		_, err := recordingController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Verify the built-in policy DRPC is waiting")
+		Expect(drAction(ctx, waitDr)()).To(Equal(ramenv1alpha1.ActionRelocate))
+		events := recordedEvents(recorder)
+		Expect(events).To(ContainElement(ContainSubstring(ReasonInFlightWaiting)))
+
+		By("Verify the selected DRPC was converted to a failover to its peer")
+		Expect(testClient.Get(ctx, client.ObjectKeyFromObject(convertDr), convertDr)).To(Succeed())
+		Expect(convertDr.Spec.Action).To(Equal(ramenv1alpha1.ActionFailover))
+		Expect(convertDr.Spec.FailoverCluster).To(Equal(peers[convertDr]))
+		Expect(events).To(ContainElement(ContainSubstring(ReasonInFlightConverted)))
+
+		By("Cleanups")
+		Expect(testClient.Delete(ctx, policy)).To(Succeed())
+		Expect(testClient.Delete(ctx, convertDr)).To(Succeed())
+		Expect(testClient.Delete(ctx, convertNs)).To(Succeed())
+		Expect(testClient.Delete(ctx, waitDr)).To(Succeed())
+		Expect(testClient.Delete(ctx, waitNs)).To(Succeed())
+		Expect(testClient.Delete(ctx, mc)).To(Succeed())
+	})
+
+	It("should wait for dr controls initiating a relocate, and not for new dr controls", func(ctx SpecContext) {
+		testName := "inflight-initiating"
+
+		By("Create an unavailable ManagedCluster")
+		mc := createUnavailableCluster(ctx, testName)
+
+		By("Create a DRPlacementControl initiating a relocate from its available peer to the MC")
+		relocateDr, relocateNs := createDRControl(ctx, testName+"-relocate", mc.Name, nil, ramenv1alpha1.Initiating,
+			drCondition(ramenv1alpha1.ConditionPeerReady, metav1.ConditionTrue))
+		relocateDr.Status.PreferredDecision.ClusterName = testName + "-relocate-peer"
+		Expect(testClient.Status().Update(ctx, relocateDr)).To(Succeed())
+		relocateDr.Spec.PreferredCluster = mc.Name
+		Expect(testClient.Update(ctx, relocateDr)).To(Succeed())
+
+		By("Create a new DRPlacementControl initiating on the MC")
+		newDr, newNs := createDRControl(ctx, testName+"-new", mc.Name, nil, ramenv1alpha1.Initiating,
+			drCondition(ramenv1alpha1.ConditionPeerReady, metav1.ConditionTrue))
+		newDr.Spec.PreferredCluster = mc.Name
+		Expect(testClient.Update(ctx, newDr)).To(Succeed())
+
+		By("Reconcile for the MC with a recording controller")
+		recorder := record.NewFakeRecorder(20)
+		recordingController := &DRTriggerController{Client: testClient, Scheme: drtController.Scheme, Recorder: recorder}
+		_, err := recordingController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Verify the relocating DRPC is waiting and the new DRPC was skipped for its phase")
+		events := recordedEvents(recorder)
+		Expect(events).To(ContainElement(ContainSubstring(
+			ReasonInFlightWaiting + " DRPlacementControl " + relocateNs.Name + "/" + relocateDr.Name)))
+		Expect(events).To(ContainElement(ContainSubstring(
+			ReasonFailoverSkippedPhase + " DRPlacementControl " + newNs.Name + "/" + newDr.Name)))
+		Expect(drAction(ctx, relocateDr)()).To(Equal(ramenv1alpha1.ActionRelocate))
+		Expect(drAction(ctx, newDr)()).To(Equal(ramenv1alpha1.ActionRelocate))
+
+		By("Cleanups")
+		Expect(testClient.Delete(ctx, newDr)).To(Succeed())
+		Expect(testClient.Delete(ctx, newNs)).To(Succeed())
+		Expect(testClient.Delete(ctx, relocateDr)).To(Succeed())
+		Expect(testClient.Delete(ctx, relocateNs)).To(Succeed())
+		Expect(testClient.Delete(ctx, mc)).To(Succeed())
+	})
+})
@@ -0,0 +1,122 @@
+// Copyright (c) 2023 Red Hat, Inc.
+
+package controller
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// DefaultClusterLeaseName is the name of the Lease renewed by the registration agent in the ManagedCluster namespace
+const DefaultClusterLeaseName = "managed-cluster-lease"
+
+// reasonClusterLeaseStale is the reason of the Available condition of a ManagedCluster marked unavailable early
+const reasonClusterLeaseStale = "ClusterLeaseStale"
+
+// LeaseMode is the way the ManagedCluster lease staleness is used for the failover decision
+type LeaseMode string
+
+const (
+	// LeaseModeEarly marks ManagedClusters with a stale lease as unavailable, before the Available condition flips
+	LeaseModeEarly LeaseMode = "early"
+	// LeaseModeConfirm requires a stale lease for confirming the outage of an unavailable ManagedCluster
+	LeaseModeConfirm LeaseMode = "confirm"
+)
+
+// LeaseDetection detects ManagedCluster outages from the staleness of the Lease renewed by their registration agent on
+// the hub, minutes earlier than the registration controller flips the Available condition.
+type LeaseDetection struct {
+	// Mode is the way the lease staleness is used, empty disables the detection
+	Mode LeaseMode
+	// LeaseName is the name of the Lease in the ManagedCluster namespace
+	LeaseName string
+	// StaleAfter is the duration since the last renewal marking the lease as stale
+	StaleAfter time.Duration
+}
+
+// enabled returns true if a mode is set with a staleness duration
+func (d LeaseDetection) enabled() bool {
+	return d.Mode != "" && d.LeaseName != "" && d.StaleAfter > 0
+}
+
+// leaseStatus is the staleness of a ManagedCluster lease
+type leaseStatus struct {
+	found     bool
+	stale     bool
+	renewedAt time.Time
+	// staleIn is the duration until a fresh lease is stale if not renewed
+	staleIn time.Duration
+}
+
+// evaluate is used for getting the staleness of the ManagedCluster lease. A missing lease, or a lease never renewed,
+// is not found.
+func (d LeaseDetection) evaluate(ctx context.Context, c client.Client, mc *clusterv1.ManagedCluster) (leaseStatus, error) {
+	if !d.enabled() {
+		return leaseStatus{}, nil
+	}
+
+	lease := &coordinationv1.Lease{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: mc.Name, Name: d.LeaseName}, lease); err != nil {
+		return leaseStatus{}, client.IgnoreNotFound(err)
+	}
+	if lease.Spec.RenewTime == nil {
+		return leaseStatus{}, nil
+	}
+
+	renewedAt := lease.Spec.RenewTime.Time
+	staleIn := d.StaleAfter - time.Since(renewedAt)
+	return leaseStatus{found: true, stale: staleIn <= 0, renewedAt: renewedAt, staleIn: max(staleIn, 0)}, nil
+}
+
+// markUnavailable is a utility function returning a copy of the ManagedCluster with its Available condition set to
+// false since the last lease renewal. The copy is only used for the failover decision, it is never updated.
+func markUnavailable(mc *clusterv1.ManagedCluster, renewedAt time.Time) *clusterv1.ManagedCluster {
+	marked := mc.DeepCopy()
+	meta.SetStatusCondition(&marked.Status.Conditions, metav1.Condition{
+		Type:               clusterv1.ManagedClusterConditionAvailable,
+		Status:             metav1.ConditionFalse,
+		Reason:             reasonClusterLeaseStale,
+		Message:            "The cluster lease was not renewed since " + renewedAt.Format(time.RFC3339),
+		LastTransitionTime: metav1.NewTime(renewedAt),
+	})
+	return marked
+}
+
+// mapLeaseToCluster is used for mapping Lease events in a ManagedCluster namespace to a request for the ManagedCluster,
+// so lease renewals resuming are evaluated right away. Only the cluster lease, and the leases of the addons used for
+// classifying outages while a DRTriggerPolicy requires whole cluster outages, are mapped.
+func (r *DRTriggerController) mapLeaseToCluster(ctx context.Context, obj client.Object) []reconcile.Request {
+	clusterLease := r.LeaseDetection.enabled() && obj.GetName() == r.LeaseDetection.LeaseName
+	if !clusterLease && (!slices.Contains(r.Outages.addonNames(), obj.GetName()) || !r.classifiesOutages(ctx)) {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: client.ObjectKey{Name: obj.GetNamespace()}}}
+}
+
+// leaseRenewalPredicate is used for filtering out the renewals of fresh leases, leaving the renewals of stale leases,
+// i.e. an agent reconnecting. Leases going stale are not an event, they are re-evaluated by requeuing once stale.
+func (r *DRTriggerController) leaseRenewalPredicate() predicate.Funcs {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			old, ok := e.ObjectOld.(*coordinationv1.Lease)
+			if !ok || old.Spec.RenewTime == nil {
+				return true
+			}
+			staleAfter := r.Outages.StaleAfter
+			if old.Name == r.LeaseDetection.LeaseName {
+				staleAfter = r.LeaseDetection.StaleAfter
+			}
+			return time.Since(old.Spec.RenewTime.Time) >= staleAfter
+		},
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2023 Red Hat, Inc.
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	ramenv1alpha1 "github.com/ramendr/ramen/api/v1alpha1"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	rdrtriggerv1alpha1 "regional-dr-trigger-operator/api/v1alpha1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"time"
+)
+
+var _ = Context("Lease Detection", func() {
+	It("should failover dr controls of an available cluster with a stale lease in early mode", func(ctx SpecContext) {
+		testName := "lease-early"
+
+		By("Create an available ManagedCluster with a lease renewed 10 minutes ago")
+		mc := createAvailableCluster(ctx, testName)
+		ns, lease := createClusterLease(ctx, mc.Name, time.Now().Add(-10*time.Minute))
+
+		By("Create a DRPlacementControl eligible for a failover")
+		drControl, drNs := createDRControl(ctx, testName, mc.Name, nil, ramenv1alpha1.Deployed,
+			drCondition(ramenv1alpha1.ConditionPeerReady, metav1.ConditionTrue))
+
+		By("Reconcile for the MC with early lease detection")
+		recorder := record.NewFakeRecorder(10)
+		leaseController := &DRTriggerController{Client: testClient, Scheme: drtController.Scheme, Recorder: recorder,
+			LeaseDetection: LeaseDetection{Mode: LeaseModeEarly, LeaseName: lease.Name, StaleAfter: 2 * time.Minute}}
+		_, err := leaseController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Verify the DRPC was failed-over")
+		Expect(drAction(ctx, drControl)()).To(Equal(ramenv1alpha1.ActionFailover))
+		Expect(recordedEvents(recorder)).To(ContainElement(ContainSubstring(ReasonClusterLeaseStale)))
+
+		By("Cleanups")
+		Expect(testClient.Delete(ctx, drControl)).To(Succeed())
+		Expect(testClient.Delete(ctx, drNs)).To(Succeed())
+		Expect(testClient.Delete(ctx, lease)).To(Succeed())
+		Expect(testClient.Delete(ctx, ns)).To(Succeed())
+	})
+
+	It("should not failover dr controls of an unavailable cluster with a renewed lease in confirm mode", func(ctx SpecContext) {
+		testName := "lease-confirm"
+
+		By("Create an unavailable ManagedCluster with a lease renewed now")
+		mc := createUnavailableClusterSince(ctx, testName, time.Now().Add(-10*time.Minute))
+		ns, lease := createClusterLease(ctx, mc.Name, time.Now())
+
+		By("Create a DRPlacementControl eligible for a failover")
+		drControl, drNs := createDRControl(ctx, testName, mc.Name, nil, ramenv1alpha1.Deployed,
+			drCondition(ramenv1alpha1.ConditionPeerReady, metav1.ConditionTrue))
+
+		By("Reconcile for the MC with confirming lease detection")
+		recorder := record.NewFakeRecorder(10)
+		leaseController := &DRTriggerController{Client: testClient, Scheme: drtController.Scheme, Recorder: recorder,
+			LeaseDetection: LeaseDetection{Mode: LeaseModeConfirm, LeaseName: lease.Name, StaleAfter: 2 * time.Minute}}
+		res, err := leaseController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Verify the DRPC was not failed-over and requeued for the lease to go stale")
+		Expect(drAction(ctx, drControl)()).To(Equal(ramenv1alpha1.ActionRelocate))
+		Expect(recordedEvents(recorder)).To(ContainElement(ContainSubstring(ReasonFailoverUnconfirmed)))
+		Expect(res.RequeueAfter).To(BeNumerically("~", 2*time.Minute, 5*time.Second))
+
+		By("Cleanups")
+		Expect(testClient.Delete(ctx, drControl)).To(Succeed())
+		Expect(testClient.Delete(ctx, drNs)).To(Succeed())
+		Expect(testClient.Delete(ctx, lease)).To(Succeed())
+		Expect(testClient.Delete(ctx, ns)).To(Succeed())
+		Expect(testClient.Delete(ctx, mc)).To(Succeed())
+	})
+
+	It("should map the cluster and addon leases to the managed cluster of their namespace", func(ctx SpecContext) {
+		leaseController := &DRTriggerController{
+			Client:         testClient,
+			LeaseDetection: LeaseDetection{Mode: LeaseModeEarly, LeaseName: DefaultClusterLeaseName, StaleAfter: time.Minute},
+			Outages:        OutageClassification{Addons: []string{"work-manager"}},
+		}
+		lease := func(name string) *coordinationv1.Lease {
+			return &coordinationv1.Lease{ObjectMeta: metav1.ObjectMeta{Namespace: "lease-map", Name: name}}
+		}
+
+		expected := []reconcile.Request{{NamespacedName: client.ObjectKey{Name: "lease-map"}}}
+		Expect(leaseController.mapLeaseToCluster(ctx, lease(DefaultClusterLeaseName))).To(Equal(expected))
+
+		By("Verify the addon leases are not mapped while no policy requires whole cluster outages")
+		Expect(leaseController.mapLeaseToCluster(ctx, lease("work-manager"))).To(BeEmpty())
+
+		By("Create a DRTriggerPolicy requiring whole cluster outages")
+		policy := &rdrtriggerv1alpha1.DRTriggerPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "lease-map"},
+			Spec:       rdrtriggerv1alpha1.DRTriggerPolicySpec{RequireWholeClusterOutage: true},
+		}
+		Expect(testClient.Create(ctx, policy)).To(Succeed())
+
+		By("Verify the leases of the inspected addons are mapped")
+		Expect(leaseController.mapLeaseToCluster(ctx, lease("work-manager"))).To(Equal(expected))
+		Expect(leaseController.mapLeaseToCluster(ctx, lease("application-manager"))).To(BeEmpty())
+
+		By("Verify the cluster lease is not mapped when the lease detection is disabled")
+		leaseController.LeaseDetection.Mode = ""
+		Expect(leaseController.mapLeaseToCluster(ctx, lease(DefaultClusterLeaseName))).To(BeEmpty())
+
+		By("Cleanups")
+		Expect(testClient.Delete(ctx, policy)).To(Succeed())
+	})
+
+	It("should only pass the renewals of stale leases", func(ctx SpecContext) {
+		leaseController := &DRTriggerController{
+			LeaseDetection: LeaseDetection{Mode: LeaseModeEarly, LeaseName: DefaultClusterLeaseName, StaleAfter: time.Minute},
+		}
+		renewal := func(renewedAt time.Time) event.UpdateEvent {
+			old := &coordinationv1.Lease{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "lease-renewal", Name: DefaultClusterLeaseName},
+				Spec:       coordinationv1.LeaseSpec{RenewTime: &metav1.MicroTime{Time: renewedAt}},
+			}
+			renewed := old.DeepCopy()
+			renewed.Spec.RenewTime = &metav1.MicroTime{Time: time.Now()}
+			return event.UpdateEvent{ObjectOld: old, ObjectNew: renewed}
+		}
+
+		predicate := leaseController.leaseRenewalPredicate()
+		Expect(predicate.Update(renewal(time.Now().Add(-10 * time.Second)))).To(BeFalse())
+		Expect(predicate.Update(renewal(time.Now().Add(-2 * time.Minute)))).To(BeTrue())
+	})
+})
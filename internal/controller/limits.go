@@ -0,0 +1,90 @@
+// Copyright (c) 2023 Red Hat, Inc.
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	rdrtriggerv1alpha1 "regional-dr-trigger-operator/api/v1alpha1"
+)
+
+// FailoverLimits caps the failovers initiated within a sliding time window, protecting the surviving clusters from
+// being overwhelmed when several clusters are unavailable together, i.e. a whole region drops. Zero caps are unlimited.
+type FailoverLimits struct {
+	// Global is the maximum number of failovers initiated within the window
+	Global int
+	// PerTargetCluster is the maximum number of failovers initiated within the window towards a single cluster
+	PerTargetCluster int
+	// Window is the duration failovers are counted for
+	Window time.Duration
+}
+
+// enabled returns true if any of the caps is set for a window
+func (l FailoverLimits) enabled() bool {
+	return l.Window > 0 && (l.Global > 0 || l.PerTargetCluster > 0)
+}
+
+// failoverBudget tracks the failovers initiated within the limits window
+type failoverBudget struct {
+	limits    FailoverLimits
+	total     int
+	perTarget map[string]int
+	oldest    time.Time
+}
+
+// loadFailoverBudget is used for loading the failovers initiated within the limits window. Failovers are counted from
+// the FailoverRecords, so the budget survives operator restarts.
+func (r *DRTriggerController) loadFailoverBudget(ctx context.Context) (*failoverBudget, error) {
+	budget := &failoverBudget{limits: r.Limits, perTarget: map[string]int{}}
+	if !r.Limits.enabled() {
+		return budget, nil
+	}
+
+	records := &rdrtriggerv1alpha1.FailoverRecordList{}
+	if err := r.Client.List(ctx, records); err != nil {
+		return nil, err
+	}
+
+	since := time.Now().Add(-r.Limits.Window)
+	for _, record := range records.Items {
+		if record.Spec.Timestamp.Time.After(since) {
+			budget.add(record.Spec.TargetCluster, record.Spec.Timestamp.Time)
+		}
+	}
+	return budget, nil
+}
+
+// allows returns true if another failover towards the target cluster is within the limits. An empty target is only
+// accounted for by the global cap.
+func (b *failoverBudget) allows(target string) bool {
+	if !b.limits.enabled() {
+		return true
+	}
+	if b.limits.Global > 0 && b.total >= b.limits.Global {
+		return false
+	}
+	if b.limits.PerTargetCluster > 0 && target != "" && b.perTarget[target] >= b.limits.PerTargetCluster {
+		return false
+	}
+	return true
+}
+
+// add is used for accounting for a failover towards the target cluster initiated at the given time
+func (b *failoverBudget) add(target string, at time.Time) {
+	b.total++
+	if target != "" {
+		b.perTarget[target]++
+	}
+	if b.oldest.IsZero() || at.Before(b.oldest) {
+		b.oldest = at
+	}
+}
+
+// retryAfter returns the duration until the oldest accounted failover leaves the limits window, freeing a slot
+func (b *failoverBudget) retryAfter() time.Duration {
+	if remaining := time.Until(b.oldest.Add(b.limits.Window)); remaining > time.Second {
+		return remaining
+	}
+	return time.Second
+}
@@ -0,0 +1,105 @@
+// Copyright (c) 2023 Red Hat, Inc.
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	ramenv1alpha1 "github.com/ramendr/ramen/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	rdrtriggerv1alpha1 "regional-dr-trigger-operator/api/v1alpha1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"time"
+)
+
+var _ = Context("Failover Limits", func() {
+	It("should queue failovers exceeding the per target cluster limit", func(ctx SpecContext) {
+		testName := "limit-per-target"
+		target := testName + "-target"
+
+		By("Create an unavailable ManagedCluster")
+		mc := createUnavailableCluster(ctx, testName)
+
+		By("Create two DRPlacementControls eligible for a failover to the same target")
+		firstDr, firstNs := createDRControl(ctx, testName+"-first", mc.Name, nil, ramenv1alpha1.Deployed,
+			drCondition(ramenv1alpha1.ConditionPeerReady, metav1.ConditionTrue))
+		secondDr, secondNs := createDRControl(ctx, testName+"-second", mc.Name, nil, ramenv1alpha1.Deployed,
+			drCondition(ramenv1alpha1.ConditionPeerReady, metav1.ConditionTrue))
+		createAvailableCluster(ctx, target)
+		drPolicy := createDRPolicy(ctx, testName, mc.Name, target)
+		for _, drControl := range []*ramenv1alpha1.DRPlacementControl{firstDr, secondDr} {
+			drControl.Spec.DRPolicyRef.Name = drPolicy.Name
+			Expect(testClient.Update(ctx, drControl)).To(Succeed())
+		}
+
+		By("Reconcile for the MC with a single failover per target cluster")
+		recorder := record.NewFakeRecorder(20)
+		limitedController := &DRTriggerController{Client: testClient, Scheme: drtController.Scheme, Recorder: recorder,
+			Limits: FailoverLimits{PerTargetCluster: 1, Window: time.Hour}}
+		res, err := limitedController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Verify only the first DRPC was failed-over")
+		Expect(drAction(ctx, firstDr)()).To(Equal(ramenv1alpha1.ActionFailover))
+		Expect(drAction(ctx, secondDr)()).To(Equal(ramenv1alpha1.ActionRelocate))
+
+		By("Verify the second DRPC failover was queued and requeued")
+		Expect(recordedEvents(recorder)).To(ContainElement(ContainSubstring(ReasonFailoverQueued)))
+		Expect(res.RequeueAfter).To(BeNumerically(">", 59*time.Minute))
+
+		By("Reconcile for the MC again, counting the failover records")
+		_, err = limitedController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Verify the second DRPC is still queued")
+		Expect(drAction(ctx, secondDr)()).To(Equal(ramenv1alpha1.ActionRelocate))
+
+		By("Cleanups")
+		Expect(testClient.DeleteAllOf(ctx, &rdrtriggerv1alpha1.FailoverRecord{}, client.InNamespace(firstNs.Name))).To(Succeed())
+		Expect(testClient.Delete(ctx, secondDr)).To(Succeed())
+		Expect(testClient.Delete(ctx, secondNs)).To(Succeed())
+		Expect(testClient.Delete(ctx, firstDr)).To(Succeed())
+		Expect(testClient.Delete(ctx, firstNs)).To(Succeed())
+		Expect(testClient.Delete(ctx, mc)).To(Succeed())
+	})
+
+	It("should report the failovers exceeding the limits as queued in dry-run mode", func(ctx SpecContext) {
+		testName := "limit-dry-run"
+
+		By("Create an unavailable ManagedCluster")
+		mc := createUnavailableCluster(ctx, testName)
+
+		By("Create two DRPlacementControls eligible for a failover")
+		firstDr, firstNs := createDRControl(ctx, testName+"-first", mc.Name, nil, ramenv1alpha1.Deployed,
+			drCondition(ramenv1alpha1.ConditionPeerReady, metav1.ConditionTrue))
+		secondDr, secondNs := createDRControl(ctx, testName+"-second", mc.Name, nil, ramenv1alpha1.Deployed,
+			drCondition(ramenv1alpha1.ConditionPeerReady, metav1.ConditionTrue))
+
+		By("Reconcile for the MC with a dry-run controller allowing a single failover")
+		recorder := record.NewFakeRecorder(20)
+		dryRunController := &DRTriggerController{Client: testClient, Scheme: drtController.Scheme, Recorder: recorder,
+			DryRun: true, Limits: FailoverLimits{Global: 1, Window: time.Hour}}
+		_, err := dryRunController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Verify no DRPC was failed-over")
+		Expect(drAction(ctx, firstDr)()).To(Equal(ramenv1alpha1.ActionRelocate))
+		Expect(drAction(ctx, secondDr)()).To(Equal(ramenv1alpha1.ActionRelocate))
+
+		By("Verify the first DRPC would have been failed-over, and the second one queued")
+		events := recordedEvents(recorder)
+		Expect(events).To(ContainElement(ContainSubstring(
+			ReasonFailoverDryRun + " DRPlacementControl " + firstNs.Name + "/" + firstDr.Name)))
+		Expect(events).To(ContainElement(ContainSubstring(
+			ReasonFailoverQueued + " DRPlacementControl " + secondNs.Name + "/" + secondDr.Name)))
+
+		By("Cleanups")
+		Expect(testClient.Delete(ctx, secondDr)).To(Succeed())
+		Expect(testClient.Delete(ctx, secondNs)).To(Succeed())
+		Expect(testClient.Delete(ctx, firstDr)).To(Succeed())
+		Expect(testClient.Delete(ctx, firstNs)).To(Succeed())
+		Expect(testClient.Delete(ctx, mc)).To(Succeed())
+	})
+})
@@ -0,0 +1,63 @@
+// Copyright (c) 2023 Red Hat, Inc.
+
+package controller
+
+import (
+	"context"
+	"strconv"
+
+	ramenv1alpha1 "github.com/ramendr/ramen/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	rdrtriggerv1alpha1 "regional-dr-trigger-operator/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// autoFailoverOptIn returns the value of the auto-failover annotation, and whether it is set. Invalid values are
+// logged and ignored.
+func autoFailoverOptIn(ctx context.Context, obj client.Object) (bool, bool) {
+	value, ok := obj.GetAnnotations()[rdrtriggerv1alpha1.AutoFailoverAnnotation]
+	if !ok {
+		return false, false
+	}
+	optIn, err := strconv.ParseBool(value)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "ignoring invalid auto-failover annotation",
+			"name", obj.GetName(), "namespace", obj.GetNamespace(), "value", value)
+		return false, false
+	}
+	return optIn, true
+}
+
+// autoFailoverSkipReason returns the reason for skipping the DRPlacementControl based on its auto-failover annotation,
+// or its Namespace's one, or an empty string if automatic failover is allowed. Namespaces are fetched once per
+// reconcile using the namespaces map.
+func (r *DRTriggerController) autoFailoverSkipReason(ctx context.Context, control ramenv1alpha1.DRPlacementControl, namespaces map[string]*corev1.Namespace) (string, error) {
+	if optIn, ok := autoFailoverOptIn(ctx, &control); ok {
+		if !optIn {
+			return "the DRPlacementControl opted out of automatic failover", nil
+		}
+		return "", nil
+	}
+
+	ns, found := namespaces[control.Namespace]
+	if !found {
+		ns = &corev1.Namespace{}
+		if err := r.Client.Get(ctx, client.ObjectKey{Name: control.Namespace}, ns); err != nil {
+			return "", err
+		}
+		namespaces[control.Namespace] = ns
+	}
+
+	if optIn, ok := autoFailoverOptIn(ctx, ns); ok {
+		if !optIn {
+			return "the Namespace opted out of automatic failover", nil
+		}
+		return "", nil
+	}
+
+	if r.OptInOnly {
+		return "automatic failover is opt-in only, and the DRPlacementControl did not opt in", nil
+	}
+	return "", nil
+}
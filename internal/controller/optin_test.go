@@ -0,0 +1,98 @@
+// Copyright (c) 2023 Red Hat, Inc.
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	ramenv1alpha1 "github.com/ramendr/ramen/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	rdrtriggerv1alpha1 "regional-dr-trigger-operator/api/v1alpha1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Context("Automatic Failover Opt-In", func() {
+	It("should not failover dr controls opted out by them or by their namespace", func(ctx SpecContext) {
+		testName := "opt-out"
+
+		By("Create an unavailable ManagedCluster")
+		mc := createUnavailableCluster(ctx, testName)
+
+		By("Create a DRPlacementControl opting out")
+		drOptOut, drOptOutNs := createDRControl(ctx, testName+"-dr", mc.Name, nil, ramenv1alpha1.Deployed,
+			drCondition(ramenv1alpha1.ConditionPeerReady, metav1.ConditionTrue))
+		drOptOut.Annotations = map[string]string{rdrtriggerv1alpha1.AutoFailoverAnnotation: "false"}
+		Expect(testClient.Update(ctx, drOptOut)).To(Succeed())
+
+		By("Create a DRPlacementControl in a Namespace opting out")
+		nsOptOut, nsOptOutNs := createDRControl(ctx, testName+"-ns", mc.Name, nil, ramenv1alpha1.Deployed,
+			drCondition(ramenv1alpha1.ConditionPeerReady, metav1.ConditionTrue))
+		nsOptOutNs.Annotations = map[string]string{rdrtriggerv1alpha1.AutoFailoverAnnotation: "false"}
+		Expect(testClient.Update(ctx, nsOptOutNs)).To(Succeed())
+
+		By("Reconcile for the MC with a recording controller")
+		recorder := record.NewFakeRecorder(20)
+		recordingController := &DRTriggerController{Client: testClient, Scheme: drtController.Scheme, Recorder: recorder}
+		_, err := recordingController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Verify both DRPCs were not failed-over")
+		Expect(drAction(ctx, drOptOut)()).To(Equal(ramenv1alpha1.ActionRelocate))
+		Expect(drAction(ctx, nsOptOut)()).To(Equal(ramenv1alpha1.ActionRelocate))
+		Expect(recordedEvents(recorder)).To(ContainElement(ContainSubstring(ReasonFailoverSkippedOptOut)))
+
+		By("Cleanups")
+		Expect(testClient.Delete(ctx, nsOptOut)).To(Succeed())
+		Expect(testClient.Delete(ctx, nsOptOutNs)).To(Succeed())
+		Expect(testClient.Delete(ctx, drOptOut)).To(Succeed())
+		Expect(testClient.Delete(ctx, drOptOutNs)).To(Succeed())
+		Expect(testClient.Delete(ctx, mc)).To(Succeed())
+	})
+
+	It("should only failover dr controls opted in when in opt-in only mode", func(ctx SpecContext) {
+		testName := "opt-in-only"
+
+		By("Create an unavailable ManagedCluster")
+		mc := createUnavailableCluster(ctx, testName)
+
+		By("Create a DRPlacementControl not opting in")
+		noOptIn, noOptInNs := createDRControl(ctx, testName+"-none", mc.Name, nil, ramenv1alpha1.Deployed,
+			drCondition(ramenv1alpha1.ConditionPeerReady, metav1.ConditionTrue))
+
+		By("Create a DRPlacementControl in a Namespace opting in")
+		nsOptIn, nsOptInNs := createDRControl(ctx, testName+"-ns", mc.Name, nil, ramenv1alpha1.Deployed,
+			drCondition(ramenv1alpha1.ConditionPeerReady, metav1.ConditionTrue))
+		nsOptInNs.Annotations = map[string]string{rdrtriggerv1alpha1.AutoFailoverAnnotation: "true"}
+		Expect(testClient.Update(ctx, nsOptInNs)).To(Succeed())
+
+		By("Create a DRPlacementControl opting in, in a Namespace opting out")
+		drOptIn, drOptInNs := createDRControl(ctx, testName+"-dr", mc.Name, nil, ramenv1alpha1.Deployed,
+			drCondition(ramenv1alpha1.ConditionPeerReady, metav1.ConditionTrue))
+		drOptIn.Annotations = map[string]string{rdrtriggerv1alpha1.AutoFailoverAnnotation: "true"}
+		Expect(testClient.Update(ctx, drOptIn)).To(Succeed())
+		drOptInNs.Annotations = map[string]string{rdrtriggerv1alpha1.AutoFailoverAnnotation: "false"}
+		Expect(testClient.Update(ctx, drOptInNs)).To(Succeed())
+
+		By("Reconcile for the MC with an opt-in only controller")
+		optInController := &DRTriggerController{
+			Client: testClient, Scheme: drtController.Scheme, Recorder: drtController.Recorder, OptInOnly: true}
+		_, err := optInController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Verify only the opted in DRPCs were failed-over")
+		Expect(drAction(ctx, noOptIn)()).To(Equal(ramenv1alpha1.ActionRelocate))
+		Expect(drAction(ctx, nsOptIn)()).To(Equal(ramenv1alpha1.ActionFailover))
+		Expect(drAction(ctx, drOptIn)()).To(Equal(ramenv1alpha1.ActionFailover))
+
+		By("Cleanups")
+		Expect(testClient.Delete(ctx, drOptIn)).To(Succeed())
+		Expect(testClient.Delete(ctx, drOptInNs)).To(Succeed())
+		Expect(testClient.Delete(ctx, nsOptIn)).To(Succeed())
+		Expect(testClient.Delete(ctx, nsOptInNs)).To(Succeed())
+		Expect(testClient.Delete(ctx, noOptIn)).To(Succeed())
+		Expect(testClient.Delete(ctx, noOptInNs)).To(Succeed())
+		Expect(testClient.Delete(ctx, mc)).To(Succeed())
+	})
+})
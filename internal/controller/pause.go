@@ -0,0 +1,53 @@
+// Copyright (c) 2023 Red Hat, Inc.
+
+package controller
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	rdrtriggerv1alpha1 "regional-dr-trigger-operator/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var operatorPausedMetric = prometheus.NewGauge(prometheus.GaugeOpts{
+	Name: "dr_operator_paused",
+	Help: "Gauge set to 1 while the Regional DR Trigger Operator is paused, evaluating without patching DRPlacementControls",
+})
+
+// paused is used for checking if the operator is paused using the pause annotation on its Namespace, updating the
+// paused gauge. Without a Namespace the operator can not be paused.
+func (r *DRTriggerController) paused(ctx context.Context) (bool, error) {
+	if r.Namespace == "" {
+		return false, nil
+	}
+
+	ns := &corev1.Namespace{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Name: r.Namespace}, ns); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+
+	paused := ns.Annotations[rdrtriggerv1alpha1.PausedAnnotation] == "true"
+	if paused {
+		operatorPausedMetric.Set(1)
+	} else {
+		operatorPausedMetric.Set(0)
+	}
+	return paused, nil
+}
+
+// mapNamespaceToClusters is used for mapping events of the operator Namespace to requests for all the ManagedClusters,
+// so pausing and resuming takes effect right away
+func (r *DRTriggerController) mapNamespaceToClusters(ctx context.Context, obj client.Object) []reconcile.Request {
+	if r.Namespace == "" || obj.GetName() != r.Namespace {
+		return nil
+	}
+	return r.selectedClusters(ctx, nil)
+}
+
+func init() {
+	metrics.Registry.MustRegister(operatorPausedMetric)
+}
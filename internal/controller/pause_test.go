@@ -0,0 +1,62 @@
+// Copyright (c) 2023 Red Hat, Inc.
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	ramenv1alpha1 "github.com/ramendr/ramen/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	rdrtriggerv1alpha1 "regional-dr-trigger-operator/api/v1alpha1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Context("Operator Pause", func() {
+	It("should not patch dr controls while the operator is paused", func(ctx SpecContext) {
+		testName := "pause"
+
+		By("Create a paused operator Namespace")
+		operatorNs := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:        testName + "-operator",
+			Annotations: map[string]string{rdrtriggerv1alpha1.PausedAnnotation: "true"},
+		}}
+		Expect(testClient.Create(ctx, operatorNs)).To(Succeed())
+
+		By("Create an unavailable ManagedCluster")
+		mc := createUnavailableCluster(ctx, testName)
+
+		By("Create a DRPlacementControl eligible for a failover")
+		drControl, ns := createDRControl(ctx, testName, mc.Name, nil, ramenv1alpha1.Deployed,
+			drCondition(ramenv1alpha1.ConditionPeerReady, metav1.ConditionTrue))
+
+		By("Reconcile for the MC")
+		recorder := record.NewFakeRecorder(10)
+		pauseController := &DRTriggerController{Client: testClient, Scheme: drtController.Scheme, Recorder: recorder,
+			Namespace: operatorNs.Name}
+		_, err := pauseController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Verify the DRPC was not failed-over and the decision was reported")
+		Expect(drAction(ctx, drControl)()).To(Equal(ramenv1alpha1.ActionRelocate))
+		Expect(recordedEvents(recorder)).To(ContainElement(ContainSubstring(ReasonFailoverPaused)))
+
+		By("Resume the operator and reconcile for the MC")
+		Expect(testClient.Get(ctx, client.ObjectKeyFromObject(operatorNs), operatorNs)).To(Succeed())
+		operatorNs.Annotations[rdrtriggerv1alpha1.PausedAnnotation] = "false"
+		Expect(testClient.Update(ctx, operatorNs)).To(Succeed())
+		_, err = pauseController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Verify the DRPC was failed-over")
+		Expect(drAction(ctx, drControl)()).To(Equal(ramenv1alpha1.ActionFailover))
+
+		By("Cleanups")
+		Expect(testClient.Delete(ctx, drControl)).To(Succeed())
+		Expect(testClient.Delete(ctx, ns)).To(Succeed())
+		Expect(testClient.Delete(ctx, operatorNs)).To(Succeed())
+		Expect(testClient.Delete(ctx, mc)).To(Succeed())
+	})
+})
@@ -0,0 +1,191 @@
+// Copyright (c) 2023 Red Hat, Inc.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	ramenv1alpha1 "github.com/ramendr/ramen/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	rdrtriggerv1alpha1 "regional-dr-trigger-operator/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// failoverPlan is the FailoverPlan of the current outage of a ManagedCluster requiring approval, collecting the
+// failover decisions of a reconcile. A nil plan requires no approval.
+type failoverPlan struct {
+	plan     *rdrtriggerv1alpha1.FailoverPlan
+	exists   bool
+	recorded bool
+	awaiting bool
+	now      time.Time
+	// saved is the status of an existing plan as loaded, before the decisions of the reconcile
+	saved rdrtriggerv1alpha1.FailoverPlanStatus
+}
+
+// approvalRequired is a utility function returning true if the ManagedCluster is marked as requiring approval
+func approvalRequired(mc *clusterv1.ManagedCluster) bool {
+	return mc.Annotations[rdrtriggerv1alpha1.FailoverApprovalRequiredAnnotation] == "true"
+}
+
+// failoverPlanName is a utility function returning the name of the FailoverPlan of the current ManagedCluster outage
+func failoverPlanName(mc *clusterv1.ManagedCluster, since time.Time) string {
+	return fmt.Sprintf("%s-%d", mc.Name, since.Unix())
+}
+
+// loadFailoverPlan is used for loading the FailoverPlan of the current outage of the ManagedCluster, a new one is
+// initialized if none exists. Returns nil if the ManagedCluster does not require approval.
+func (r *DRTriggerController) loadFailoverPlan(ctx context.Context, mc *clusterv1.ManagedCluster) (*failoverPlan, error) {
+	if !approvalRequired(mc) {
+		return nil, nil
+	}
+
+	since := metav1.NewTime(unavailableSince(mc))
+
+	plan := &rdrtriggerv1alpha1.FailoverPlan{}
+	err := r.Client.Get(ctx, client.ObjectKey{Name: failoverPlanName(mc, since.Time)}, plan)
+	if err == nil {
+		p := &failoverPlan{plan: plan, exists: true, now: time.Now(), saved: *plan.Status.DeepCopy()}
+		p.capApproval(r.ApprovalMaxDuration)
+		return p, nil
+	}
+	if !k8serrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	plan = &rdrtriggerv1alpha1.FailoverPlan{
+		ObjectMeta: metav1.ObjectMeta{Name: failoverPlanName(mc, since.Time)},
+		Spec:       rdrtriggerv1alpha1.FailoverPlanSpec{ManagedCluster: mc.Name, UnavailableSince: since},
+	}
+	return &failoverPlan{plan: plan, now: time.Now()}, nil
+}
+
+// capApproval is used for capping the expiry of a new approval to the maximum duration since it was observed, a zero
+// maximum duration does not cap it. The capped expiry is kept until a new generation of the plan carries a new one.
+func (p *failoverPlan) capApproval(maxDuration time.Duration) {
+	status := &p.plan.Status
+	if p.plan.Spec.Approval == nil {
+		status.ApprovalExpiresAt = nil
+		return
+	}
+	if status.ApprovalExpiresAt != nil && status.ObservedGeneration == p.plan.Generation {
+		return
+	}
+
+	expiresAt := p.plan.Spec.Approval.ExpiresAt
+	if maxDuration > 0 && p.now.Add(maxDuration).Before(expiresAt.Time) {
+		expiresAt = metav1.NewTime(p.now.Add(maxDuration))
+	}
+	status.ApprovalExpiresAt = &expiresAt
+	status.ObservedGeneration = p.plan.Generation
+}
+
+// approved returns true if the plan was approved and the approval did not expire, no plan is always approved
+func (p *failoverPlan) approved() bool {
+	return p == nil || p.plan.IsApproved(p.now)
+}
+
+// record is used for recording the gating state of a DRPlacementControl in the plan, and the reason of its failover
+// decision, replacing a previous one
+func (p *failoverPlan) record(control ramenv1alpha1.DRPlacementControl, target string, state rdrtriggerv1alpha1.FailoverPlanEntryState, reason, message string) {
+	if p == nil {
+		return
+	}
+	p.recorded = true
+	if state == rdrtriggerv1alpha1.FailoverPlanEntryAwaitingApproval {
+		p.awaiting = true
+	}
+
+	entry := rdrtriggerv1alpha1.FailoverPlanEntry{
+		DRPlacementControl: control.Name,
+		Namespace:          control.Namespace,
+		TargetCluster:      target,
+		State:              state,
+		Reason:             reason,
+		Message:            message,
+	}
+	entries := p.plan.Status.Entries
+	if i := slices.IndexFunc(entries, func(e rdrtriggerv1alpha1.FailoverPlanEntry) bool {
+		return e.DRPlacementControl == control.Name && e.Namespace == control.Namespace
+	}); i >= 0 {
+		entries[i] = entry
+	} else {
+		p.plan.Status.Entries = append(entries, entry)
+	}
+}
+
+// saveFailoverPlan is used for saving the plan with the recorded entries. A new plan is created once a
+// DRPlacementControl of the ManagedCluster is recorded, whether awaiting approval or gated.
+func (r *DRTriggerController) saveFailoverPlan(ctx context.Context, mc *clusterv1.ManagedCluster, p *failoverPlan) error {
+	if p == nil || (!p.exists && !p.recorded) {
+		return nil
+	}
+
+	status := p.plan.Status.DeepCopy()
+	status.Phase = p.plan.GetPhase(p.now)
+	if !p.exists {
+		if err := r.Client.Create(ctx, p.plan); err != nil {
+			return err
+		}
+		log.FromContext(ctx).Info("created failover plan", "plan", p.plan.Name, "awaiting_approval", p.awaiting)
+		if p.awaiting {
+			r.Recorder.Eventf(mc, corev1.EventTypeWarning, ReasonFailoverPlanCreated,
+				"Failover awaiting approval, approve FailoverPlan %s for failing over", p.plan.Name)
+		} else {
+			r.Recorder.Eventf(mc, corev1.EventTypeNormal, ReasonFailoverPlanCreated,
+				"FailoverPlan %s created, no failover is awaiting approval yet", p.plan.Name)
+		}
+	} else if equality.Semantic.DeepEqual(p.saved, *status) {
+		return nil
+	}
+
+	p.plan.Status = *status
+	return r.Client.Status().Update(ctx, p.plan)
+}
+
+// closeFailoverPlans is used for closing the FailoverPlans of past outages of the ManagedCluster, superseded by a new
+// outage or ended by the cluster recovery, so their approvals are no longer executed
+func (r *DRTriggerController) closeFailoverPlans(ctx context.Context, mc *clusterv1.ManagedCluster) error {
+	current := ""
+	if !meta.IsStatusConditionTrue(mc.Status.Conditions, clusterv1.ManagedClusterConditionAvailable) {
+		current = failoverPlanName(mc, unavailableSince(mc))
+	}
+
+	plans := &rdrtriggerv1alpha1.FailoverPlanList{}
+	if err := r.Client.List(ctx, plans); err != nil {
+		return err
+	}
+
+	for _, plan := range plans.Items {
+		if plan.Spec.ManagedCluster != mc.Name || plan.Name == current ||
+			plan.Status.Phase == rdrtriggerv1alpha1.FailoverPlanClosed {
+			continue
+		}
+		plan.Status.Phase = rdrtriggerv1alpha1.FailoverPlanClosed
+		if err := r.Client.Status().Update(ctx, &plan); err != nil {
+			return err
+		}
+		log.FromContext(ctx).Info("closed failover plan of a past outage", "plan", plan.Name)
+	}
+	return nil
+}
+
+// mapPlanToCluster is used for mapping a FailoverPlan to a reconcile request for its ManagedCluster, so an approval is
+// executed right away
+func mapPlanToCluster(_ context.Context, obj client.Object) []reconcile.Request {
+	plan, ok := obj.(*rdrtriggerv1alpha1.FailoverPlan)
+	if !ok {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: client.ObjectKey{Name: plan.Spec.ManagedCluster}}}
+}
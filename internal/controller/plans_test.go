@@ -0,0 +1,197 @@
+// Copyright (c) 2023 Red Hat, Inc.
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	ramenv1alpha1 "github.com/ramendr/ramen/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	rdrtriggerv1alpha1 "regional-dr-trigger-operator/api/v1alpha1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"time"
+)
+
+var _ = Context("Failover Plans", func() {
+	It("should failover dr controls of a cluster requiring approval only once the plan is approved", func(ctx SpecContext) {
+		testName := "failover-plan"
+
+		By("Create an unavailable ManagedCluster requiring approval")
+		mc := createUnavailableCluster(ctx, testName)
+		mc.Annotations = map[string]string{rdrtriggerv1alpha1.FailoverApprovalRequiredAnnotation: "true"}
+		Expect(testClient.Update(ctx, mc)).To(Succeed())
+
+		By("Create a DRPlacementControl eligible for a failover")
+		drControl, ns := createDRControl(ctx, testName, mc.Name, nil, ramenv1alpha1.Deployed,
+			drCondition(ramenv1alpha1.ConditionPeerReady, metav1.ConditionTrue))
+
+		By("Reconcile for the MC")
+		recorder := record.NewFakeRecorder(10)
+		planController := &DRTriggerController{Client: testClient, Scheme: drtController.Scheme, Recorder: recorder}
+		_, err := planController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Verify the DRPC was not failed-over and a pending plan lists it")
+		Expect(drAction(ctx, drControl)()).To(Equal(ramenv1alpha1.ActionRelocate))
+		Expect(recordedEvents(recorder)).To(ContainElement(ContainSubstring(ReasonFailoverPlanCreated)))
+
+		plans := &rdrtriggerv1alpha1.FailoverPlanList{}
+		Expect(testClient.List(ctx, plans)).To(Succeed())
+		var plan *rdrtriggerv1alpha1.FailoverPlan
+		for i := range plans.Items {
+			if plans.Items[i].Spec.ManagedCluster == mc.Name {
+				plan = &plans.Items[i]
+			}
+		}
+		Expect(plan).NotTo(BeNil())
+		Expect(plan.Status.Phase).To(Equal(rdrtriggerv1alpha1.FailoverPlanPending))
+		Expect(plan.Status.Entries).To(HaveLen(1))
+		Expect(plan.Status.Entries[0]).To(Equal(rdrtriggerv1alpha1.FailoverPlanEntry{
+			DRPlacementControl: drControl.Name,
+			Namespace:          drControl.Namespace,
+			TargetCluster:      testName + "-peer",
+			State:              rdrtriggerv1alpha1.FailoverPlanEntryAwaitingApproval,
+			Reason:             ReasonFailoverAwaitingApproval,
+		}))
+
+		By("Approve the plan")
+		plan.Spec.Approval = &rdrtriggerv1alpha1.FailoverPlanApproval{
+			ApprovedBy: "jane@example.com",
+			ExpiresAt:  metav1.NewTime(time.Now().Add(time.Hour)),
+		}
+		Expect(testClient.Update(ctx, plan)).To(Succeed())
+
+		By("Reconcile for the MC")
+		_, err = planController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Verify the DRPC was failed-over and the plan executed")
+		Expect(drAction(ctx, drControl)()).To(Equal(ramenv1alpha1.ActionFailover))
+		Expect(testClient.Get(ctx, client.ObjectKeyFromObject(plan), plan)).To(Succeed())
+		Expect(plan.Status.Phase).To(Equal(rdrtriggerv1alpha1.FailoverPlanApproved))
+		Expect(plan.Status.ApprovalExpiresAt).NotTo(BeNil())
+		Expect(plan.Status.ApprovalExpiresAt.Time).To(BeTemporally("~", plan.Spec.Approval.ExpiresAt.Time, time.Second))
+		Expect(plan.Status.Entries).To(HaveLen(1))
+		Expect(plan.Status.Entries[0].State).To(Equal(rdrtriggerv1alpha1.FailoverPlanEntryFailoverTriggered))
+
+		By("Cleanups")
+		Expect(testClient.Delete(ctx, plan)).To(Succeed())
+		Expect(testClient.DeleteAllOf(ctx, &rdrtriggerv1alpha1.FailoverRecord{}, client.InNamespace(ns.Name))).To(Succeed())
+		Expect(testClient.Delete(ctx, drControl)).To(Succeed())
+		Expect(testClient.Delete(ctx, ns)).To(Succeed())
+		Expect(testClient.Delete(ctx, mc)).To(Succeed())
+	})
+
+	It("should not execute a plan with an expired approval", func() {
+		plan := &rdrtriggerv1alpha1.FailoverPlan{Spec: rdrtriggerv1alpha1.FailoverPlanSpec{
+			Approval: &rdrtriggerv1alpha1.FailoverPlanApproval{
+				ApprovedBy: "jane@example.com",
+				ExpiresAt:  metav1.NewTime(time.Now().Add(-time.Minute)),
+			},
+		}}
+		Expect(plan.IsApproved(time.Now())).To(BeFalse())
+		Expect(plan.GetPhase(time.Now())).To(Equal(rdrtriggerv1alpha1.FailoverPlanExpired))
+	})
+
+	It("should cap the expiry of an approval to the maximum approval duration", func() {
+		plan := &failoverPlan{now: time.Now(), plan: &rdrtriggerv1alpha1.FailoverPlan{
+			ObjectMeta: metav1.ObjectMeta{Generation: 2},
+			Spec: rdrtriggerv1alpha1.FailoverPlanSpec{Approval: &rdrtriggerv1alpha1.FailoverPlanApproval{
+				ApprovedBy: "jane@example.com",
+				ExpiresAt:  metav1.NewTime(time.Now().Add(30 * 24 * time.Hour)),
+			}},
+		}}
+		plan.capApproval(time.Hour)
+		Expect(plan.plan.Status.ApprovalExpiresAt.Time).To(BeTemporally("~", time.Now().Add(time.Hour), time.Second))
+		Expect(plan.plan.IsApproved(time.Now())).To(BeTrue())
+		Expect(plan.plan.IsApproved(time.Now().Add(2 * time.Hour))).To(BeFalse())
+
+		By("Verify the capped expiry is kept until a new approval")
+		plan.now = time.Now().Add(time.Hour)
+		plan.capApproval(time.Hour)
+		Expect(plan.plan.IsApproved(time.Now().Add(90 * time.Minute))).To(BeFalse())
+
+		plan.plan.Generation = 3
+		plan.capApproval(time.Hour)
+		Expect(plan.plan.IsApproved(time.Now().Add(90 * time.Minute))).To(BeTrue())
+	})
+
+	It("should list every dr control of the cluster in the plan with the reason of its decision", func(ctx SpecContext) {
+		testName := "failover-plan-gated"
+
+		By("Create an unavailable ManagedCluster requiring approval")
+		mc := createUnavailableCluster(ctx, testName)
+		mc.Annotations = map[string]string{rdrtriggerv1alpha1.FailoverApprovalRequiredAnnotation: "true"}
+		Expect(testClient.Update(ctx, mc)).To(Succeed())
+
+		By("Create a DRPlacementControl in the middle of a relocate")
+		drControl, ns := createDRControl(ctx, testName, mc.Name, nil, ramenv1alpha1.Relocating)
+
+		By("Reconcile for the MC")
+		planController := &DRTriggerController{Client: testClient, Scheme: drtController.Scheme,
+			Recorder: record.NewFakeRecorder(10)}
+		_, err := planController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Verify a plan was created listing the DRPC as gated with its decision reason")
+		plan := &rdrtriggerv1alpha1.FailoverPlan{}
+		Expect(testClient.Get(ctx, client.ObjectKey{Name: failoverPlanName(mc, unavailableSince(mc))}, plan)).To(Succeed())
+		Expect(plan.Status.Entries).To(HaveLen(1))
+		Expect(plan.Status.Entries[0].State).To(Equal(rdrtriggerv1alpha1.FailoverPlanEntryGated))
+		Expect(plan.Status.Entries[0].Reason).To(Equal(ReasonInFlightWaiting))
+
+		By("Cleanups")
+		Expect(testClient.Delete(ctx, plan)).To(Succeed())
+		Expect(testClient.Delete(ctx, drControl)).To(Succeed())
+		Expect(testClient.Delete(ctx, ns)).To(Succeed())
+		Expect(testClient.Delete(ctx, mc)).To(Succeed())
+	})
+
+	It("should close the plan of a past outage and ignore its approval", func(ctx SpecContext) {
+		testName := "failover-plan-closed"
+
+		By("Create an unavailable ManagedCluster requiring approval")
+		mc := createUnavailableCluster(ctx, testName)
+		mc.Annotations = map[string]string{rdrtriggerv1alpha1.FailoverApprovalRequiredAnnotation: "true"}
+		Expect(testClient.Update(ctx, mc)).To(Succeed())
+
+		By("Create a DRPlacementControl eligible for a failover")
+		drControl, ns := createDRControl(ctx, testName, mc.Name, nil, ramenv1alpha1.Deployed,
+			drCondition(ramenv1alpha1.ConditionPeerReady, metav1.ConditionTrue))
+
+		By("Create an approved plan of a past outage")
+		since := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+		pastPlan := &rdrtriggerv1alpha1.FailoverPlan{
+			ObjectMeta: metav1.ObjectMeta{Name: failoverPlanName(mc, since.Time)},
+			Spec: rdrtriggerv1alpha1.FailoverPlanSpec{
+				ManagedCluster:   mc.Name,
+				UnavailableSince: since,
+				Approval: &rdrtriggerv1alpha1.FailoverPlanApproval{
+					ApprovedBy: "jane@example.com",
+					ExpiresAt:  metav1.NewTime(time.Now().Add(time.Hour)),
+				},
+			},
+		}
+		Expect(testClient.Create(ctx, pastPlan)).To(Succeed())
+
+		By("Reconcile for the MC")
+		planController := &DRTriggerController{Client: testClient, Scheme: drtController.Scheme,
+			Recorder: record.NewFakeRecorder(10)}
+		_, err := planController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Verify the past plan was closed and the DRPC was not failed-over")
+		Expect(testClient.Get(ctx, client.ObjectKeyFromObject(pastPlan), pastPlan)).To(Succeed())
+		Expect(pastPlan.Status.Phase).To(Equal(rdrtriggerv1alpha1.FailoverPlanClosed))
+		Expect(pastPlan.IsApproved(time.Now())).To(BeFalse())
+		Expect(drAction(ctx, drControl)()).To(Equal(ramenv1alpha1.ActionRelocate))
+
+		By("Cleanups")
+		Expect(testClient.DeleteAllOf(ctx, &rdrtriggerv1alpha1.FailoverPlan{})).To(Succeed())
+		Expect(testClient.Delete(ctx, drControl)).To(Succeed())
+		Expect(testClient.Delete(ctx, ns)).To(Succeed())
+		Expect(testClient.Delete(ctx, mc)).To(Succeed())
+	})
+})
@@ -0,0 +1,167 @@
+// Copyright (c) 2023 Red Hat, Inc.
+
+package controller
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	ramenv1alpha1 "github.com/ramendr/ramen/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	rdrtriggerv1alpha1 "regional-dr-trigger-operator/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// defaultPolicy carries the built-in failover rules, used for DRPlacementControls not selected by any DRTriggerPolicy.
+var defaultPolicy = &rdrtriggerv1alpha1.DRTriggerPolicy{
+	Spec: rdrtriggerv1alpha1.DRTriggerPolicySpec{
+		AllowedPhases:      []ramenv1alpha1.DRState{ramenv1alpha1.Deploying, ramenv1alpha1.Deployed, ramenv1alpha1.Relocated},
+		RequiredConditions: []string{ramenv1alpha1.ConditionPeerReady},
+	},
+}
+
+// listPolicies is used for listing all DRTriggerPolicies sorted by name, the order used for selecting a policy.
+func (r *DRTriggerController) listPolicies(ctx context.Context) ([]rdrtriggerv1alpha1.DRTriggerPolicy, error) {
+	policies := &rdrtriggerv1alpha1.DRTriggerPolicyList{}
+	if err := r.Client.List(ctx, policies); err != nil {
+		return nil, err
+	}
+	sort.Slice(policies.Items, func(i, j int) bool {
+		return policies.Items[i].Name < policies.Items[j].Name
+	})
+	return policies.Items, nil
+}
+
+// selectPolicy returns the first policy selecting both the ManagedCluster and the DRPlacementControl. If no policy
+// selects them, the built-in defaultPolicy is returned. Policies with invalid selectors are logged and ignored.
+func selectPolicy(ctx context.Context, policies []rdrtriggerv1alpha1.DRTriggerPolicy, mc *clusterv1.ManagedCluster, control ramenv1alpha1.DRPlacementControl) *rdrtriggerv1alpha1.DRTriggerPolicy {
+	logger := log.FromContext(ctx)
+	for i := range policies {
+		policy := &policies[i]
+		clusterMatch, err := selectorMatches(policy.Spec.ClusterSelector, mc.Labels)
+		if err != nil {
+			logger.Error(err, "invalid cluster selector, ignoring policy", "policy", policy.Name)
+			continue
+		}
+		controlMatch, err := selectorMatches(policy.Spec.DRPlacementControlSelector, control.Labels)
+		if err != nil {
+			logger.Error(err, "invalid dr control selector, ignoring policy", "policy", policy.Name)
+			continue
+		}
+		if clusterMatch && controlMatch {
+			return policy
+		}
+	}
+	return defaultPolicy
+}
+
+// selectorMatches is a utility function returning true if the label selector matches the labels. A nil selector
+// matches everything.
+func selectorMatches(selector *metav1.LabelSelector, set map[string]string) (bool, error) {
+	if selector == nil {
+		return true, nil
+	}
+	s, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return false, err
+	}
+	return s.Matches(labels.Set(set)), nil
+}
+
+// isPhaseOkForFailover is a utility function that returns true if the DRPlacementControl.Status.Phase is in a state
+// allowed for failing over by the policy. i.e., Deployed.
+func isPhaseOkForFailover(policy *rdrtriggerv1alpha1.DRTriggerPolicy, control ramenv1alpha1.DRPlacementControl) bool {
+	for _, state := range policy.Spec.AllowedPhases {
+		if state == control.Status.Phase {
+			return true
+		}
+	}
+	return false
+}
+
+// unmetCondition is a utility function that returns the first condition type required by the policy that is not true
+// for the DRPlacementControl. An empty string is returned if all the required conditions are met.
+func unmetCondition(policy *rdrtriggerv1alpha1.DRTriggerPolicy, control ramenv1alpha1.DRPlacementControl) string {
+	for _, condition := range policy.Spec.RequiredConditions {
+		if !meta.IsStatusConditionTrue(control.Status.Conditions, condition) {
+			return condition
+		}
+	}
+	return ""
+}
+
+// defaultLeaseDurationSeconds is the lease duration used by the Klusterlet agent when the ManagedCluster does not set one
+const defaultLeaseDurationSeconds = 60
+
+// gracePeriod is a utility function returning the duration a ManagedCluster is required to be unavailable before
+// failing over the DRPlacementControls selected by the policy. An explicit grace period takes precedence over one
+// derived from the cluster's lease duration. No grace period is required if neither is set.
+func gracePeriod(policy *rdrtriggerv1alpha1.DRTriggerPolicy, mc *clusterv1.ManagedCluster) time.Duration {
+	if policy.Spec.UnavailableGracePeriod != nil {
+		return policy.Spec.UnavailableGracePeriod.Duration
+	}
+	if policy.Spec.UnavailableLeaseMultiplier != nil {
+		leaseSeconds := mc.Spec.LeaseDurationSeconds
+		if leaseSeconds == 0 {
+			leaseSeconds = defaultLeaseDurationSeconds
+		}
+		return time.Duration(*policy.Spec.UnavailableLeaseMultiplier*leaseSeconds) * time.Second
+	}
+	return 0
+}
+
+// unavailableFor is a utility function returning the duration a ManagedCluster is unavailable for, measured from the
+// last transition time of its Available condition. The status is kept on the cluster, so the duration survives
+// operator restarts and leader changes.
+func unavailableFor(mc *clusterv1.ManagedCluster) time.Duration {
+	return time.Since(unavailableSince(mc))
+}
+
+// unavailableSince is a utility function returning the last transition time of the ManagedCluster Available
+// condition. A cluster never reporting availability is measured from its creation.
+func unavailableSince(mc *clusterv1.ManagedCluster) time.Time {
+	if condition := meta.FindStatusCondition(mc.Status.Conditions, clusterv1.ManagedClusterConditionAvailable); condition != nil {
+		return condition.LastTransitionTime.Time
+	}
+	return mc.CreationTimestamp.Time
+}
+
+// policyName is a utility function used for logging the name of a policy, the built-in policy has no name.
+func policyName(policy *rdrtriggerv1alpha1.DRTriggerPolicy) string {
+	if policy == defaultPolicy {
+		return "built-in"
+	}
+	return policy.Name
+}
+
+// mapPolicyToClusters is used for mapping DRTriggerPolicy events to requests for the ManagedClusters it selects
+func (r *DRTriggerController) mapPolicyToClusters(ctx context.Context, obj client.Object) []reconcile.Request {
+	policy, ok := obj.(*rdrtriggerv1alpha1.DRTriggerPolicy)
+	if !ok {
+		return nil
+	}
+	return r.selectedClusters(ctx, policy.Spec.ClusterSelector)
+}
+
+// selectedClusters is used for getting requests for the ManagedClusters selected by a cluster selector
+func (r *DRTriggerController) selectedClusters(ctx context.Context, selector *metav1.LabelSelector) []reconcile.Request {
+	mcs := &clusterv1.ManagedClusterList{}
+	if err := r.Client.List(ctx, mcs); err != nil {
+		log.FromContext(ctx).Error(err, "failed listing managed clusters for selector")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, mc := range mcs.Items {
+		if match, err := selectorMatches(selector, mc.Labels); err == nil && match {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&mc)})
+		}
+	}
+	return requests
+}
@@ -0,0 +1,238 @@
+// Copyright (c) 2023 Red Hat, Inc.
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	ramenv1alpha1 "github.com/ramendr/ramen/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rdrtriggerv1alpha1 "regional-dr-trigger-operator/api/v1alpha1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"time"
+)
+
+var _ = Context("DR Trigger Policy", func() {
+	It("should not failover dr controls selected by a disabled policy", func(ctx SpecContext) {
+		testName := "policy-disabled"
+		selected := map[string]string{"test": testName}
+
+		By("Create an unavailable ManagedCluster")
+		mc := createUnavailableCluster(ctx, testName)
+
+		By("Create a DRPlacementControl eligible for a failover")
+		drControl, ns := createDRControl(ctx, testName, mc.Name, selected, ramenv1alpha1.Deployed,
+			drCondition(ramenv1alpha1.ConditionPeerReady, metav1.ConditionTrue))
+
+		By("Create a disabled DRTriggerPolicy selecting the DRPlacementControl")
+		enabled := false
+		policy := &rdrtriggerv1alpha1.DRTriggerPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: testName},
+			Spec: rdrtriggerv1alpha1.DRTriggerPolicySpec{
+				Enabled:                    &enabled,
+				DRPlacementControlSelector: &metav1.LabelSelector{MatchLabels: selected},
+			},
+		}
+		Expect(testClient.Create(ctx, policy)).To(Succeed())
+
+		By("Reconcile for the MC")
+		res, err := drtController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)})
+		Expect(res.Requeue).To(BeFalse())
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Verify the DRPC was not failed-over")
+		Consistently(drAction(ctx, drControl)).Should(Equal(ramenv1alpha1.ActionRelocate))
+
+		By("Cleanups")
+		Expect(testClient.Delete(ctx, policy)).To(Succeed())
+		Expect(testClient.Delete(ctx, drControl)).To(Succeed())
+		Expect(testClient.Delete(ctx, ns)).To(Succeed())
+		Expect(testClient.Delete(ctx, mc)).To(Succeed())
+	})
+
+	It("should failover dr controls in a phase allowed by the selecting policy", func(ctx SpecContext) {
+		testName := "policy-allowed-phases"
+		selected := map[string]string{"test": testName}
+
+		By("Create an unavailable ManagedCluster")
+		mc := createUnavailableCluster(ctx, testName)
+
+		By("Create a DRPlacementControl in a phase not allowed by the built-in rules")
+		drControl, ns := createDRControl(ctx, testName, mc.Name, selected, ramenv1alpha1.Initiating,
+			drCondition(ramenv1alpha1.ConditionPeerReady, metav1.ConditionTrue))
+
+		By("Create a DRTriggerPolicy allowing the phase")
+		policy := &rdrtriggerv1alpha1.DRTriggerPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: testName},
+			Spec: rdrtriggerv1alpha1.DRTriggerPolicySpec{
+				DRPlacementControlSelector: &metav1.LabelSelector{MatchLabels: selected},
+				AllowedPhases:              []ramenv1alpha1.DRState{ramenv1alpha1.Initiating},
+			},
+		}
+		Expect(testClient.Create(ctx, policy)).To(Succeed())
+
+		By("Reconcile for the MC")
+		res, err := drtController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)})
+		Expect(res.Requeue).To(BeFalse())
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Verify the DRPC was failed-over")
+		Eventually(drAction(ctx, drControl)).Should(Equal(ramenv1alpha1.ActionFailover))
+
+		By("Cleanups")
+		Expect(testClient.Delete(ctx, policy)).To(Succeed())
+		Expect(testClient.Delete(ctx, drControl)).To(Succeed())
+		Expect(testClient.Delete(ctx, ns)).To(Succeed())
+		Expect(testClient.Delete(ctx, mc)).To(Succeed())
+	})
+
+	It("should only require the conditions listed by the selecting policy", func(ctx SpecContext) {
+		testName := "policy-required-conditions"
+		selected := map[string]string{"test": testName}
+
+		By("Create an unavailable ManagedCluster")
+		mc := createUnavailableCluster(ctx, testName)
+
+		By("Create a protected DRPlacementControl with its peer not ready")
+		drControl, ns := createDRControl(ctx, testName, mc.Name, selected, ramenv1alpha1.Deployed,
+			drCondition(ramenv1alpha1.ConditionPeerReady, metav1.ConditionFalse),
+			drCondition(ramenv1alpha1.ConditionProtected, metav1.ConditionTrue))
+
+		By("Create a DRTriggerPolicy only requiring the protected condition")
+		policy := &rdrtriggerv1alpha1.DRTriggerPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: testName},
+			Spec: rdrtriggerv1alpha1.DRTriggerPolicySpec{
+				DRPlacementControlSelector: &metav1.LabelSelector{MatchLabels: selected},
+				RequiredConditions:         []string{ramenv1alpha1.ConditionProtected},
+			},
+		}
+		Expect(testClient.Create(ctx, policy)).To(Succeed())
+
+		By("Reconcile for the MC")
+		res, err := drtController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)})
+		Expect(res.Requeue).To(BeFalse())
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Verify the DRPC was failed-over")
+		Eventually(drAction(ctx, drControl)).Should(Equal(ramenv1alpha1.ActionFailover))
+
+		By("Cleanups")
+		Expect(testClient.Delete(ctx, policy)).To(Succeed())
+		Expect(testClient.Delete(ctx, drControl)).To(Succeed())
+		Expect(testClient.Delete(ctx, ns)).To(Succeed())
+		Expect(testClient.Delete(ctx, mc)).To(Succeed())
+	})
+
+	It("should postpone failing over until the cluster was unavailable for the grace period", func(ctx SpecContext) {
+		testName := "policy-grace-period-pending"
+		selected := map[string]string{"test": testName}
+
+		By("Create a ManagedCluster unavailable for a minute")
+		mc := createUnavailableClusterSince(ctx, testName, time.Now().Add(-time.Minute))
+
+		By("Create a DRPlacementControl eligible for a failover")
+		drControl, ns := createDRControl(ctx, testName, mc.Name, selected, ramenv1alpha1.Deployed,
+			drCondition(ramenv1alpha1.ConditionPeerReady, metav1.ConditionTrue))
+
+		By("Create a DRTriggerPolicy with a grace period of an hour")
+		policy := &rdrtriggerv1alpha1.DRTriggerPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: testName},
+			Spec: rdrtriggerv1alpha1.DRTriggerPolicySpec{
+				DRPlacementControlSelector: &metav1.LabelSelector{MatchLabels: selected},
+				UnavailableGracePeriod:     &metav1.Duration{Duration: time.Hour},
+			},
+		}
+		Expect(testClient.Create(ctx, policy)).To(Succeed())
+
+		By("Reconcile for the MC")
+		res, err := drtController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Verify the request was requeued for the remaining grace period")
+		Expect(res.RequeueAfter).To(BeNumerically(">", 58*time.Minute))
+		Expect(res.RequeueAfter).To(BeNumerically("<=", 59*time.Minute))
+
+		By("Verify the DRPC was not failed-over")
+		Consistently(drAction(ctx, drControl)).Should(Equal(ramenv1alpha1.ActionRelocate))
+
+		By("Cleanups")
+		Expect(testClient.Delete(ctx, policy)).To(Succeed())
+		Expect(testClient.Delete(ctx, drControl)).To(Succeed())
+		Expect(testClient.Delete(ctx, ns)).To(Succeed())
+		Expect(testClient.Delete(ctx, mc)).To(Succeed())
+	})
+
+	It("should failover once the cluster was unavailable for the lease derived grace period", func(ctx SpecContext) {
+		testName := "policy-grace-period-lease"
+		selected := map[string]string{"test": testName}
+
+		By("Create a ManagedCluster unavailable for ten minutes")
+		mc := createUnavailableClusterSince(ctx, testName, time.Now().Add(-10*time.Minute))
+
+		By("Create a DRPlacementControl eligible for a failover")
+		drControl, ns := createDRControl(ctx, testName, mc.Name, selected, ramenv1alpha1.Deployed,
+			drCondition(ramenv1alpha1.ConditionPeerReady, metav1.ConditionTrue))
+
+		By("Create a DRTriggerPolicy waiting for five missed leases")
+		multiplier := int32(5)
+		policy := &rdrtriggerv1alpha1.DRTriggerPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: testName},
+			Spec: rdrtriggerv1alpha1.DRTriggerPolicySpec{
+				DRPlacementControlSelector: &metav1.LabelSelector{MatchLabels: selected},
+				UnavailableLeaseMultiplier: &multiplier,
+			},
+		}
+		Expect(testClient.Create(ctx, policy)).To(Succeed())
+
+		By("Reconcile for the MC")
+		res, err := drtController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)})
+		Expect(res.RequeueAfter).To(BeZero())
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Verify the DRPC was failed-over")
+		Eventually(drAction(ctx, drControl)).Should(Equal(ramenv1alpha1.ActionFailover))
+
+		By("Cleanups")
+		Expect(testClient.Delete(ctx, policy)).To(Succeed())
+		Expect(testClient.Delete(ctx, drControl)).To(Succeed())
+		Expect(testClient.Delete(ctx, ns)).To(Succeed())
+		Expect(testClient.Delete(ctx, mc)).To(Succeed())
+	})
+
+	It("should not failover dr controls selected by a dry-run policy", func(ctx SpecContext) {
+		testName := "policy-dry-run"
+		selected := map[string]string{"test": testName}
+
+		By("Create an unavailable ManagedCluster")
+		mc := createUnavailableCluster(ctx, testName)
+
+		By("Create a DRPlacementControl eligible for a failover")
+		drControl, ns := createDRControl(ctx, testName, mc.Name, selected, ramenv1alpha1.Deployed,
+			drCondition(ramenv1alpha1.ConditionPeerReady, metav1.ConditionTrue))
+
+		By("Create a dry-run DRTriggerPolicy selecting the DRPlacementControl")
+		policy := &rdrtriggerv1alpha1.DRTriggerPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: testName},
+			Spec: rdrtriggerv1alpha1.DRTriggerPolicySpec{
+				DryRun:                     true,
+				DRPlacementControlSelector: &metav1.LabelSelector{MatchLabels: selected},
+			},
+		}
+		Expect(testClient.Create(ctx, policy)).To(Succeed())
+
+		By("Reconcile for the MC")
+		res, err := drtController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)})
+		Expect(res.Requeue).To(BeFalse())
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Verify the DRPC was not failed-over")
+		Consistently(drAction(ctx, drControl)).Should(Equal(ramenv1alpha1.ActionRelocate))
+
+		By("Cleanups")
+		Expect(testClient.Delete(ctx, policy)).To(Succeed())
+		Expect(testClient.Delete(ctx, drControl)).To(Succeed())
+		Expect(testClient.Delete(ctx, ns)).To(Succeed())
+		Expect(testClient.Delete(ctx, mc)).To(Succeed())
+	})
+})
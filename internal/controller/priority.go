@@ -0,0 +1,76 @@
+// Copyright (c) 2023 Red Hat, Inc.
+
+package controller
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"time"
+
+	ramenv1alpha1 "github.com/ramendr/ramen/api/v1alpha1"
+	rdrtriggerv1alpha1 "regional-dr-trigger-operator/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// priorityRequeueInterval is the interval for requeueing while lower priority DRPlacementControls are held
+const priorityRequeueInterval = 30 * time.Second
+
+// failoverPriority returns the DRPlacementControl failover priority, invalid priorities are logged and ignored
+func failoverPriority(ctx context.Context, control ramenv1alpha1.DRPlacementControl) int {
+	value, ok := control.Annotations[rdrtriggerv1alpha1.FailoverPriorityAnnotation]
+	if !ok {
+		return 0
+	}
+	priority, err := strconv.Atoi(value)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "ignoring invalid failover priority", "drpc_name",
+			control.Name, "drpc_ns", control.Namespace, "priority", value)
+		return 0
+	}
+	return priority
+}
+
+// sortByPriority is used for sorting DRPlacementControls by their failover priority, higher priorities first. The
+// List order is kept for DRPlacementControls of the same priority.
+func sortByPriority(ctx context.Context, controls []ramenv1alpha1.DRPlacementControl) {
+	sort.SliceStable(controls, func(i, j int) bool {
+		return failoverPriority(ctx, controls[i]) > failoverPriority(ctx, controls[j])
+	})
+}
+
+// phaseReached returns true if the phase is the release phase, or the phase following it in a failover process
+func phaseReached(phase, release ramenv1alpha1.DRState) bool {
+	if phase == release {
+		return true
+	}
+	return release == ramenv1alpha1.FailingOver && phase == ramenv1alpha1.FailedOver
+}
+
+// priorityGate holds lower priority DRPlacementControls while a higher priority one is pending, i.e. it is postponed,
+// queued, or failing over without reaching the release phase. Expects the DRPlacementControls sorted by priority.
+type priorityGate struct {
+	blocking bool
+	priority int
+}
+
+// holds returns true if a higher priority DRPlacementControl is pending
+func (g *priorityGate) holds(priority int) bool {
+	return g.blocking && priority < g.priority
+}
+
+// pending is used for marking a DRPlacementControl of the given priority as pending
+func (g *priorityGate) pending(priority int) {
+	if !g.blocking {
+		g.blocking, g.priority = true, priority
+	}
+}
+
+// releasePhase returns the phase higher priority DRPlacementControls are required to reach before releasing the
+// lower ones, defaults to FailedOver
+func (r *DRTriggerController) releasePhase() ramenv1alpha1.DRState {
+	if r.PriorityReleasePhase == "" {
+		return ramenv1alpha1.FailedOver
+	}
+	return r.PriorityReleasePhase
+}
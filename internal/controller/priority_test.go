@@ -0,0 +1,71 @@
+// Copyright (c) 2023 Red Hat, Inc.
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	ramenv1alpha1 "github.com/ramendr/ramen/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rdrtriggerv1alpha1 "regional-dr-trigger-operator/api/v1alpha1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Context("Failover Priority", func() {
+	It("should hold lower priority dr controls until the higher ones failed over", func(ctx SpecContext) {
+		testName := "priority-sequencing"
+
+		By("Create an unavailable ManagedCluster")
+		mc := createUnavailableCluster(ctx, testName)
+
+		By("Create a DRPlacementControl with the default priority")
+		appDr, appNs := createDRControl(ctx, testName+"-app", mc.Name, nil, ramenv1alpha1.Deployed,
+			drCondition(ramenv1alpha1.ConditionPeerReady, metav1.ConditionTrue))
+
+		By("Create a DRPlacementControl with a higher priority")
+		dbDr, dbNs := createDRControl(ctx, testName+"-db", mc.Name, nil, ramenv1alpha1.Deployed,
+			drCondition(ramenv1alpha1.ConditionPeerReady, metav1.ConditionTrue))
+		dbDr.Annotations = map[string]string{rdrtriggerv1alpha1.FailoverPriorityAnnotation: "10"}
+		Expect(testClient.Update(ctx, dbDr)).To(Succeed())
+
+		By("Reconcile for the MC")
+		res, err := drtController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Verify only the higher priority DRPC was failed-over")
+		Expect(drAction(ctx, dbDr)()).To(Equal(ramenv1alpha1.ActionFailover))
+		Expect(drAction(ctx, appDr)()).To(Equal(ramenv1alpha1.ActionRelocate))
+		Expect(res.RequeueAfter).To(Equal(priorityRequeueInterval))
+
+		By("Update the higher priority DRPC phase to FailingOver")
+		Expect(testClient.Get(ctx, client.ObjectKeyFromObject(dbDr), dbDr)).To(Succeed())
+		dbDr.Status.Phase = ramenv1alpha1.FailingOver
+		Expect(testClient.Status().Update(ctx, dbDr)).To(Succeed())
+
+		By("Reconcile for the MC")
+		_, err = drtController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Verify the lower priority DRPC is still held")
+		Expect(drAction(ctx, appDr)()).To(Equal(ramenv1alpha1.ActionRelocate))
+
+		By("Update the higher priority DRPC phase to FailedOver")
+		dbDr.Status.Phase = ramenv1alpha1.FailedOver
+		Expect(testClient.Status().Update(ctx, dbDr)).To(Succeed())
+
+		By("Reconcile for the MC")
+		_, err = drtController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Verify the lower priority DRPC was released and failed-over")
+		Expect(drAction(ctx, appDr)()).To(Equal(ramenv1alpha1.ActionFailover))
+
+		By("Cleanups")
+		Expect(testClient.Delete(ctx, dbDr)).To(Succeed())
+		Expect(testClient.Delete(ctx, dbNs)).To(Succeed())
+		Expect(testClient.Delete(ctx, appDr)).To(Succeed())
+		Expect(testClient.Delete(ctx, appNs)).To(Succeed())
+		Expect(testClient.Delete(ctx, mc)).To(Succeed())
+	})
+})
@@ -0,0 +1,170 @@
+// Copyright (c) 2023 Red Hat, Inc.
+
+package controller
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// ClusterPlaceholder is replaced with the name of the ManagedCluster in the Prometheus queries
+const ClusterPlaceholder = "$cluster"
+
+var prometheusQueryFailureMetric = *prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "dr_prometheus_query_failure_count",
+	Help: "Counter for Prometheus queries failed while confirming ManagedCluster outages",
+}, []string{"dr_cluster_name"})
+
+// PrometheusSignal confirms ManagedCluster outages using PromQL queries against a Prometheus compatible HTTP API, i.e.
+// Thanos of ACM Observability. Like alerting rules, a query agrees the cluster is down when it returns a result.
+type PrometheusSignal struct {
+	// URL is the base URL of the Prometheus compatible HTTP API, empty disables the signal
+	URL string
+	// Queries are the PromQL queries, all of them must agree the cluster is down
+	Queries []string
+	// BearerTokenFile is the path of a file holding a token for authenticating the queries, optional
+	BearerTokenFile string
+	// Interval is the duration after which held failovers are queried again
+	Interval time.Duration
+	// Timeout is the duration after which a query is abandoned, 0 disables it
+	Timeout time.Duration
+	// Client is the HTTP client used for querying, the default client is used when not set
+	Client *http.Client
+}
+
+// NewPrometheusClient is a factory function for creating the HTTP client used for querying Prometheus. The queries are
+// abandoned after the timeout. When set, the server certificate is verified using the CA bundle file, i.e. the
+// service CA of an in-cluster Thanos, otherwise using the system roots.
+func NewPrometheusClient(caFile string, timeout time.Duration) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if caFile != "" {
+		ca, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed reading prometheus ca file %s, %v", caFile, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("no certificates found in prometheus ca file %s", caFile)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+	return &http.Client{Transport: transport, Timeout: timeout}, nil
+}
+
+// enabled returns true if a URL is set with queries
+func (s PrometheusSignal) enabled() bool {
+	return s.URL != "" && len(s.Queries) > 0
+}
+
+// queryResponse is the response of the Prometheus instant query API
+type queryResponse struct {
+	Status    string `json:"status"`
+	ErrorType string `json:"errorType"`
+	Error     string `json:"error"`
+	Data      struct {
+		ResultType string          `json:"resultType"`
+		Result     json.RawMessage `json:"result"`
+	} `json:"data"`
+}
+
+// confirmsOutage is used for running the queries for the named ManagedCluster. Returns the first query not agreeing
+// the cluster is down, empty if all of them agree.
+func (s PrometheusSignal) confirmsOutage(ctx context.Context, cluster string) (string, error) {
+	for _, query := range s.Queries {
+		query = strings.ReplaceAll(query, ClusterPlaceholder, cluster)
+		down, err := s.query(ctx, query)
+		if err != nil {
+			prometheusQueryFailureMetric.WithLabelValues(cluster).Inc()
+			return query, err
+		}
+		if !down {
+			return query, nil
+		}
+	}
+	return "", nil
+}
+
+// query is used for running an instant query, returning true if it returned a result. A vector or a matrix result
+// must not be empty, a scalar result must not be zero.
+func (s PrometheusSignal) query(ctx context.Context, query string) (bool, error) {
+	endpoint, err := url.JoinPath(s.URL, "api/v1/query")
+	if err != nil {
+		return false, fmt.Errorf("invalid prometheus url %s, %v", s.URL, err)
+	}
+
+	if s.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.Timeout)
+		defer cancel()
+	}
+
+	form := url.Values{"query": {query}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if s.BearerTokenFile != "" {
+		token, err := os.ReadFile(s.BearerTokenFile)
+		if err != nil {
+			return false, fmt.Errorf("failed reading prometheus bearer token, %v", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+
+	httpClient := s.Client
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("failed querying prometheus, %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return false, fmt.Errorf("failed reading prometheus response, %v", err)
+	}
+	qr := &queryResponse{}
+	if err := json.Unmarshal(body, qr); err != nil {
+		return false, fmt.Errorf("failed parsing prometheus response with status %d, %v", resp.StatusCode, err)
+	}
+	if qr.Status != "success" {
+		return false, fmt.Errorf("prometheus query failed with %s, %s", qr.ErrorType, qr.Error)
+	}
+
+	switch qr.Data.ResultType {
+	case "vector", "matrix":
+		var samples []json.RawMessage
+		if err := json.Unmarshal(qr.Data.Result, &samples); err != nil {
+			return false, fmt.Errorf("failed parsing prometheus %s result, %v", qr.Data.ResultType, err)
+		}
+		return len(samples) > 0, nil
+	case "scalar":
+		var sample []any
+		if err := json.Unmarshal(qr.Data.Result, &sample); err != nil || len(sample) != 2 {
+			return false, fmt.Errorf("failed parsing prometheus scalar result, %v", err)
+		}
+		value, _ := sample[1].(string)
+		return value != "" && value != "0", nil
+	default:
+		return false, fmt.Errorf("unsupported prometheus result type %s", qr.Data.ResultType)
+	}
+}
+
+func init() {
+	metrics.Registry.MustRegister(prometheusQueryFailureMetric)
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2023 Red Hat, Inc.
+
+package controller
+
+import (
+	"encoding/pem"
+	"fmt"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	ramenv1alpha1 "github.com/ramendr/ramen/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sync"
+	"time"
+)
+
+var _ = Context("Prometheus Signal", func() {
+	It("should failover dr controls only once the prometheus query agrees the cluster is down", func(ctx SpecContext) {
+		testName := "prometheus"
+
+		By("Start a stand-in Prometheus returning no result for the query")
+		var mu sync.Mutex
+		var queries []string
+		result := `[]`
+		prom := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			queries = append(queries, req.FormValue("query"))
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprintf(w, `{"status":"success","data":{"resultType":"vector","result":%s}}`, result)
+		}))
+		DeferCleanup(prom.Close)
+
+		By("Create an unavailable ManagedCluster")
+		mc := createUnavailableCluster(ctx, testName)
+
+		By("Create a DRPlacementControl eligible for a failover")
+		drControl, ns := createDRControl(ctx, testName, mc.Name, nil, ramenv1alpha1.Deployed,
+			drCondition(ramenv1alpha1.ConditionPeerReady, metav1.ConditionTrue))
+
+		By("Reconcile for the MC with the prometheus signal")
+		recorder := record.NewFakeRecorder(10)
+		promController := &DRTriggerController{Client: testClient, Scheme: drtController.Scheme, Recorder: recorder,
+			Prometheus: PrometheusSignal{URL: prom.URL, Queries: []string{`up{cluster="$cluster"} == 0`},
+				Interval: time.Minute}}
+		res, err := promController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Verify the DRPC was not failed-over and the cluster name was set in the query")
+		Expect(drAction(ctx, drControl)()).To(Equal(ramenv1alpha1.ActionRelocate))
+		Expect(recordedEvents(recorder)).To(ContainElement(ContainSubstring(ReasonFailoverUnconfirmed)))
+		Expect(res.RequeueAfter).To(Equal(time.Minute))
+		mu.Lock()
+		Expect(queries).To(Equal([]string{`up{cluster="prometheus"} == 0`}))
+		mu.Unlock()
+
+		By("Make the stand-in Prometheus return a result and reconcile for the MC")
+		mu.Lock()
+		result = `[{"metric":{"cluster":"prometheus"},"value":[1700000000,"0"]}]`
+		mu.Unlock()
+		_, err = promController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Verify the DRPC was failed-over")
+		Expect(drAction(ctx, drControl)()).To(Equal(ramenv1alpha1.ActionFailover))
+
+		By("Cleanups")
+		Expect(testClient.Delete(ctx, drControl)).To(Succeed())
+		Expect(testClient.Delete(ctx, ns)).To(Succeed())
+		Expect(testClient.Delete(ctx, mc)).To(Succeed())
+	})
+
+	It("should not confirm outages when the prometheus query fails", func(ctx SpecContext) {
+		By("Start a stand-in Prometheus failing the query")
+		prom := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = fmt.Fprint(w, `{"status":"error","errorType":"bad_data","error":"parse error"}`)
+		}))
+		DeferCleanup(prom.Close)
+
+		By("Query the stand-in Prometheus")
+		signal := PrometheusSignal{URL: prom.URL, Queries: []string{"up{"}}
+		query, err := signal.confirmsOutage(ctx, "prometheus-failing")
+		Expect(err).To(MatchError(ContainSubstring("parse error")))
+		Expect(query).To(Equal("up{"))
+	})
+
+	It("should abandon prometheus queries after the timeout", func(ctx SpecContext) {
+		By("Start a stand-in Prometheus not responding in time")
+		release := make(chan struct{})
+		prom := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) {
+			select {
+			case <-release:
+			case <-req.Context().Done():
+			}
+		}))
+		DeferCleanup(prom.Close)
+		DeferCleanup(func() { close(release) })
+
+		By("Query the stand-in Prometheus")
+		signal := PrometheusSignal{URL: prom.URL, Queries: []string{"up"}, Timeout: 100 * time.Millisecond}
+		start := time.Now()
+		_, err := signal.confirmsOutage(ctx, "prometheus-timeout")
+		Expect(err).To(MatchError(ContainSubstring("context deadline exceeded")))
+		Expect(time.Since(start)).To(BeNumerically("<", 5*time.Second))
+	})
+
+	It("should verify the prometheus server certificate using the ca file", func(ctx SpecContext) {
+		By("Start a stand-in Prometheus served over tls")
+		prom := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprint(w, `{"status":"success","data":{"resultType":"vector","result":[{"value":[1,"1"]}]}}`)
+		}))
+		DeferCleanup(prom.Close)
+
+		By("Verify querying fails without the ca file")
+		client, err := NewPrometheusClient("", time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		signal := PrometheusSignal{URL: prom.URL, Queries: []string{"up"}, Client: client}
+		_, err = signal.confirmsOutage(ctx, "prometheus-tls")
+		Expect(err).To(MatchError(ContainSubstring("certificate")))
+
+		By("Verify querying succeeds with the ca file")
+		caFile := filepath.Join(GinkgoT().TempDir(), "ca.crt")
+		ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: prom.Certificate().Raw})
+		Expect(os.WriteFile(caFile, ca, 0o600)).To(Succeed())
+		signal.Client, err = NewPrometheusClient(caFile, time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		query, err := signal.confirmsOutage(ctx, "prometheus-tls")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(query).To(BeEmpty())
+
+		By("Verify a ca file without certificates is rejected")
+		Expect(os.WriteFile(caFile, []byte("not a certificate"), 0o600)).To(Succeed())
+		_, err = NewPrometheusClient(caFile, time.Minute)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,162 @@
+// Copyright (c) 2023 Red Hat, Inc.
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"strings"
+
+	ramenv1alpha1 "github.com/ramendr/ramen/api/v1alpha1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	rdrtriggerv1alpha1 "regional-dr-trigger-operator/api/v1alpha1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// recordNameHashLength is the length of the hash suffixing truncated FailoverRecord names
+const recordNameHashLength = 10
+
+// FailoverRecordController is a receiver representing the controller following DRPlacementControls failed over by
+// the operator, updating the status of their FailoverRecords
+type FailoverRecordController struct {
+	Client client.Client
+	Scheme *runtime.Scheme
+}
+
+// SetupWithManager is used for setting up the controller. FailoverRecord events are mapped to their
+// DRPlacementControl, so a phase change predating the record creation is not missed.
+func (r *FailoverRecordController) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("failover-record-controller").
+		For(&ramenv1alpha1.DRPlacementControl{}).
+		Watches(&rdrtriggerv1alpha1.FailoverRecord{}, handler.EnqueueRequestsFromMapFunc(mapRecordToDRControl)).
+		Complete(r)
+}
+
+// +kubebuilder:rbac:groups=rdrtrigger.redhat.com,resources=failoverrecords,verbs=get;watch;list;create;delete
+// +kubebuilder:rbac:groups=rdrtrigger.redhat.com,resources=failoverrecords/status,verbs=get;update;patch
+
+// Reconcile is watching DRPlacementControls and will update the status of their FailoverRecords with the current
+// DRPlacementControl phase. Records are no longer updated once the DRPlacementControl was observed as FailedOver.
+func (r *FailoverRecordController) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx).WithName("record-controller")
+
+	drControl := &ramenv1alpha1.DRPlacementControl{}
+	if err := r.Client.Get(ctx, req.NamespacedName, drControl); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// only dr controls being failed over are followed
+	if drControl.Spec.Action != ramenv1alpha1.ActionFailover {
+		return ctrl.Result{}, nil
+	}
+
+	records := &rdrtriggerv1alpha1.FailoverRecordList{}
+	if err := r.Client.List(ctx, records, client.InNamespace(drControl.Namespace)); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	for _, record := range records.Items {
+		if record.Spec.DRPlacementControl != drControl.Name || record.IsCompleted() {
+			continue
+		}
+		if record.Status.Phase == drControl.Status.Phase {
+			continue
+		}
+		// followed once ramen moved from the phase before the failover, a second failover starts from FailedOver
+		if record.Status.Phase == "" && drControl.Status.Phase == record.Spec.PhaseBeforeFailover {
+			continue
+		}
+
+		record.Status.Phase = drControl.Status.Phase
+		if record.IsCompleted() {
+			now := metav1.Now()
+			record.Status.CompletionTime = &now
+		}
+		if err := r.Client.Status().Update(ctx, &record); err != nil {
+			return ctrl.Result{}, err
+		}
+		logger.Info("updated failover record", "record_name", record.Name, "record_ns", record.Namespace,
+			"dr_phase", record.Status.Phase)
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// mapRecordToDRControl is used for mapping a FailoverRecord to a reconcile request for its DRPlacementControl
+func mapRecordToDRControl(_ context.Context, obj client.Object) []reconcile.Request {
+	record, ok := obj.(*rdrtriggerv1alpha1.FailoverRecord)
+	if !ok || record.IsCompleted() {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{
+		Namespace: record.Namespace, Name: record.Spec.DRPlacementControl}}}
+}
+
+// failoverRecordName is a utility function returning the name of the FailoverRecord of a DRPlacementControl failover
+// during the current ManagedCluster outage, so retrying a failed patch reuses the record created before it. Names
+// longer than allowed are truncated, suffixed with a hash of the full name, keeping them unique per failover.
+func failoverRecordName(mc *clusterv1.ManagedCluster, control ramenv1alpha1.DRPlacementControl) string {
+	name := fmt.Sprintf("%s-%s-%d", control.Name, mc.Name, unavailableSince(mc).Unix())
+	if len(name) <= validation.DNS1123SubdomainMaxLength {
+		return name
+	}
+	hash := fmt.Sprintf("%x", sha256.Sum256([]byte(name)))[:recordNameHashLength]
+	prefix := strings.TrimRight(name[:validation.DNS1123SubdomainMaxLength-recordNameHashLength-1], "-.")
+	return prefix + "-" + hash
+}
+
+// createFailoverRecord is used for creating a FailoverRecord for a DRPlacementControl about to be patched for a
+// failover to the target cluster, with the reason of a break-glass forcing it. The record is created before the patch,
+// so a failover is never initiated without one, an existing record of the same failover is kept.
+func (r *DRTriggerController) createFailoverRecord(ctx context.Context, mc *clusterv1.ManagedCluster, control ramenv1alpha1.DRPlacementControl, policy *rdrtriggerv1alpha1.DRTriggerPolicy, target, breakGlassReason string) error {
+	peerReady := metav1.ConditionUnknown
+	if condition := meta.FindStatusCondition(control.Status.Conditions, ramenv1alpha1.ConditionPeerReady); condition != nil {
+		peerReady = condition.Status
+	}
+
+	record := &rdrtriggerv1alpha1.FailoverRecord{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      failoverRecordName(mc, control),
+			Namespace: control.Namespace,
+		},
+		Spec: rdrtriggerv1alpha1.FailoverRecordSpec{
+			DRPlacementControl:       control.Name,
+			ManagedCluster:           mc.Name,
+			TargetCluster:            target,
+			ManagedClusterConditions: mc.Status.Conditions,
+			PhaseBeforeFailover:      control.Status.Phase,
+			PeerReady:                peerReady,
+			Policy:                   policyName(policy),
+			BreakGlassReason:         breakGlassReason,
+			OperatorVersion:          r.Version,
+			Timestamp:                metav1.Now(),
+		},
+	}
+
+	if err := r.Client.Create(ctx, record); err != nil && !k8serrors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+// deleteFailoverRecord is used for deleting the FailoverRecord created for a DRPlacementControl failover, when patching
+// the DRPlacementControl failed
+func (r *DRTriggerController) deleteFailoverRecord(ctx context.Context, mc *clusterv1.ManagedCluster, control ramenv1alpha1.DRPlacementControl) error {
+	record := &rdrtriggerv1alpha1.FailoverRecord{ObjectMeta: metav1.ObjectMeta{
+		Name:      failoverRecordName(mc, control),
+		Namespace: control.Namespace,
+	}}
+	return client.IgnoreNotFound(r.Client.Delete(ctx, record))
+}
@@ -0,0 +1,155 @@
+// Copyright (c) 2023 Red Hat, Inc.
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	ramenv1alpha1 "github.com/ramendr/ramen/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	rdrtriggerv1alpha1 "regional-dr-trigger-operator/api/v1alpha1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"strings"
+)
+
+var _ = Context("Failover Records", func() {
+	It("should create a failover record when patching a dr control", func(ctx SpecContext) {
+		testName := "record-created"
+
+		By("Create an unavailable ManagedCluster")
+		mc := createUnavailableCluster(ctx, testName)
+
+		By("Create a DRPlacementControl eligible for a failover")
+		drControl, ns := createDRControl(ctx, testName, mc.Name, nil, ramenv1alpha1.Deployed,
+			drCondition(ramenv1alpha1.ConditionPeerReady, metav1.ConditionTrue))
+
+		By("Reconcile for the MC with a versioned controller")
+		versionedController := &DRTriggerController{
+			Client: testClient, Scheme: drtController.Scheme, Recorder: drtController.Recorder, Version: "1.2.3"}
+		_, err := versionedController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Verify a failover record was created")
+		records := &rdrtriggerv1alpha1.FailoverRecordList{}
+		Expect(testClient.List(ctx, records, client.InNamespace(ns.Name))).To(Succeed())
+		Expect(records.Items).To(HaveLen(1))
+
+		record := records.Items[0]
+		Expect(record.Spec.DRPlacementControl).To(Equal(drControl.Name))
+		Expect(record.Spec.ManagedCluster).To(Equal(mc.Name))
+		Expect(record.Spec.ManagedClusterConditions).To(HaveLen(2))
+		Expect(record.Spec.PhaseBeforeFailover).To(Equal(ramenv1alpha1.Deployed))
+		Expect(record.Spec.PeerReady).To(Equal(metav1.ConditionTrue))
+		Expect(record.Spec.Policy).To(Equal("built-in"))
+		Expect(record.Spec.OperatorVersion).To(Equal("1.2.3"))
+		Expect(record.Spec.Timestamp.IsZero()).To(BeFalse())
+
+		By("Cleanups")
+		Expect(testClient.Delete(ctx, &record)).To(Succeed())
+		Expect(testClient.Delete(ctx, drControl)).To(Succeed())
+		Expect(testClient.Delete(ctx, ns)).To(Succeed())
+		Expect(testClient.Delete(ctx, mc)).To(Succeed())
+	})
+
+	It("should follow the dr control phase until failed over", func(ctx SpecContext) {
+		testName := "record-follows-phase"
+
+		By("Create an unavailable ManagedCluster")
+		mc := createUnavailableCluster(ctx, testName)
+
+		By("Create a DRPlacementControl eligible for a failover")
+		drControl, ns := createDRControl(ctx, testName, mc.Name, nil, ramenv1alpha1.Deployed,
+			drCondition(ramenv1alpha1.ConditionPeerReady, metav1.ConditionTrue))
+
+		By("Reconcile for the MC")
+		_, err := drtController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)})
+		Expect(err).NotTo(HaveOccurred())
+
+		recordController := &FailoverRecordController{Client: testClient, Scheme: drtController.Scheme}
+		records := &rdrtriggerv1alpha1.FailoverRecordList{}
+		for _, phase := range []ramenv1alpha1.DRState{ramenv1alpha1.FailingOver, ramenv1alpha1.FailedOver} {
+			By("Update the DRPC phase to " + string(phase))
+			Expect(testClient.Get(ctx, client.ObjectKeyFromObject(drControl), drControl)).To(Succeed())
+			drControl.Status.Phase = phase
+			Expect(testClient.Status().Update(ctx, drControl)).To(Succeed())
+
+			By("Reconcile for the DRPC")
+			_, err = recordController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(drControl)})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Verify the failover record follows the DRPC phase")
+			Expect(testClient.List(ctx, records, client.InNamespace(ns.Name))).To(Succeed())
+			Expect(records.Items).To(HaveLen(1))
+			Expect(records.Items[0].Status.Phase).To(Equal(phase))
+		}
+		Expect(records.Items[0].Status.CompletionTime).NotTo(BeNil())
+
+		By("Update the DRPC phase after the failover completed")
+		drControl.Status.Phase = ramenv1alpha1.Relocating
+		Expect(testClient.Status().Update(ctx, drControl)).To(Succeed())
+
+		By("Reconcile for the DRPC")
+		_, err = recordController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(drControl)})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Verify the completed failover record is no longer updated")
+		Expect(testClient.List(ctx, records, client.InNamespace(ns.Name))).To(Succeed())
+		Expect(records.Items[0].Status.Phase).To(Equal(ramenv1alpha1.FailedOver))
+
+		By("Cleanups")
+		Expect(testClient.Delete(ctx, &records.Items[0])).To(Succeed())
+		Expect(testClient.Delete(ctx, drControl)).To(Succeed())
+		Expect(testClient.Delete(ctx, ns)).To(Succeed())
+		Expect(testClient.Delete(ctx, mc)).To(Succeed())
+	})
+
+	It("should truncate long failover record names, keeping them unique", func() {
+		mc := &clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: strings.Repeat("c", 63)}}
+		control := ramenv1alpha1.DRPlacementControl{ObjectMeta: metav1.ObjectMeta{Name: strings.Repeat("d", 253)}}
+		other := ramenv1alpha1.DRPlacementControl{ObjectMeta: metav1.ObjectMeta{Name: strings.Repeat("d", 252)}}
+
+		name := failoverRecordName(mc, control)
+		Expect(name).To(HaveLen(validation.DNS1123SubdomainMaxLength))
+		Expect(validation.IsDNS1123Subdomain(name)).To(BeEmpty())
+		Expect(failoverRecordName(mc, control)).To(Equal(name))
+		Expect(failoverRecordName(mc, other)).NotTo(Equal(name))
+
+		By("Verify short names are not truncated")
+		control.Name = "short"
+		Expect(failoverRecordName(mc, control)).To(HavePrefix("short-" + mc.Name + "-"))
+	})
+
+	It("should reuse the failover record created before a failed patch when retrying", func(ctx SpecContext) {
+		testName := "record-retried"
+
+		By("Create an unavailable ManagedCluster")
+		mc := createUnavailableCluster(ctx, testName)
+
+		By("Create a DRPlacementControl eligible for a failover")
+		drControl, ns := createDRControl(ctx, testName, mc.Name, nil, ramenv1alpha1.Deployed,
+			drCondition(ramenv1alpha1.ConditionPeerReady, metav1.ConditionTrue))
+
+		By("Create the failover record left by a failed patch")
+		Expect(drtController.createFailoverRecord(ctx, mc, *drControl, defaultPolicy, testName+"-peer", "")).To(Succeed())
+
+		By("Reconcile for the MC")
+		_, err := drtController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Verify the DRPC was failed-over with a single failover record")
+		Expect(drAction(ctx, drControl)()).To(Equal(ramenv1alpha1.ActionFailover))
+		records := &rdrtriggerv1alpha1.FailoverRecordList{}
+		Expect(testClient.List(ctx, records, client.InNamespace(ns.Name))).To(Succeed())
+		Expect(records.Items).To(HaveLen(1))
+		Expect(records.Items[0].Name).To(Equal(failoverRecordName(mc, *drControl)))
+
+		By("Cleanups")
+		Expect(testClient.Delete(ctx, &records.Items[0])).To(Succeed())
+		Expect(testClient.Delete(ctx, drControl)).To(Succeed())
+		Expect(testClient.Delete(ctx, ns)).To(Succeed())
+		Expect(testClient.Delete(ctx, mc)).To(Succeed())
+	})
+})
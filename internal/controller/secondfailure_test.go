@@ -0,0 +1,45 @@
+// Copyright (c) 2023 Red Hat, Inc.
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	ramenv1alpha1 "github.com/ramendr/ramen/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Context("Second Failure", func() {
+	It("should failover dr controls failed over to an unavailable cluster back to their peer", func(ctx SpecContext) {
+		testName := "second-failure"
+		original := testName + "-peer"
+
+		By("Create an unavailable ManagedCluster")
+		mc := createUnavailableCluster(ctx, testName)
+
+		By("Create a DRPlacementControl failed over from its available peer to the MC")
+		drControl, ns := createDRControl(ctx, testName, mc.Name, nil, ramenv1alpha1.FailedOver,
+			drCondition(ramenv1alpha1.ConditionPeerReady, metav1.ConditionTrue))
+		drControl.Status.PreferredDecision.ClusterName = original
+		Expect(testClient.Status().Update(ctx, drControl)).To(Succeed())
+		drControl.Spec.Action = ramenv1alpha1.ActionFailover
+		drControl.Spec.FailoverCluster = mc.Name
+		Expect(testClient.Update(ctx, drControl)).To(Succeed())
+
+		By("Reconcile for the MC")
+		_, err := drtController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Verify the DRPC was failed-over back to the original cluster")
+		Expect(testClient.Get(ctx, client.ObjectKeyFromObject(drControl), drControl)).To(Succeed())
+		Expect(drControl.Spec.Action).To(Equal(ramenv1alpha1.ActionFailover))
+		Expect(drControl.Spec.FailoverCluster).To(Equal(original))
+
+		By("Cleanups")
+		Expect(testClient.Delete(ctx, drControl)).To(Succeed())
+		Expect(testClient.Delete(ctx, ns)).To(Succeed())
+		Expect(testClient.Delete(ctx, mc)).To(Succeed())
+	})
+})
@@ -0,0 +1,128 @@
+// Copyright (c) 2023 Red Hat, Inc.
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	ramenv1alpha1 "github.com/ramendr/ramen/api/v1alpha1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	rdrtriggerv1alpha1 "regional-dr-trigger-operator/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// maxSilenceDuration is the longest duration of a scheduled silence, bounding the schedule lookup
+const maxSilenceDuration = 7 * 24 * time.Hour
+
+var drApplicationFailoverSilencedMetric = *prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "dr_application_failover_silenced_count",
+	Help: "Counter for DR Applications failover the Regional DR Trigger Operator would have initiated if not silenced",
+}, []string{"dr_cluster_name", "dr_control_name", "dr_application_name", "dr_silence_name"})
+
+// silenceRemaining is a utility function returning the remaining duration of the silence at the given time, 0 if it
+// is not active. Silences with an invalid schedule are never active.
+func silenceRemaining(silence *rdrtriggerv1alpha1.FailoverSilence, now time.Time) (time.Duration, error) {
+	spec := silence.Spec
+	if spec.StartTime != nil && now.Before(spec.StartTime.Time) {
+		return 0, nil
+	}
+	if spec.EndTime != nil && !now.Before(spec.EndTime.Time) {
+		return 0, nil
+	}
+
+	// a time range silence
+	if spec.Schedule == "" {
+		if spec.EndTime == nil {
+			return 0, nil
+		}
+		return spec.EndTime.Sub(now), nil
+	}
+
+	// a recurring silence, active for its duration every time its schedule fires
+	if spec.Duration == nil {
+		return 0, nil
+	}
+	schedule, err := parseCronSchedule(spec.Schedule)
+	if err != nil {
+		return 0, err
+	}
+	duration := min(spec.Duration.Duration, maxSilenceDuration)
+	fired := lastFiredWithin(schedule, now, duration)
+	if fired.IsZero() {
+		return 0, nil
+	}
+	remaining := fired.Add(duration).Sub(now)
+	if spec.EndTime != nil {
+		remaining = min(remaining, spec.EndTime.Sub(now))
+	}
+	return remaining, nil
+}
+
+// activeFailoverSilence is a FailoverSilence active at the time of the reconcile, with its remaining duration
+type activeFailoverSilence struct {
+	silence   *rdrtriggerv1alpha1.FailoverSilence
+	remaining time.Duration
+}
+
+// activeSilences returns the silences active at the given time and selecting the ManagedCluster, with their remaining
+// duration. Silences with invalid cluster selectors or schedules are logged and ignored.
+func activeSilences(ctx context.Context, silences []rdrtriggerv1alpha1.FailoverSilence, mc *clusterv1.ManagedCluster, now time.Time) []activeFailoverSilence {
+	logger := log.FromContext(ctx)
+	var active []activeFailoverSilence
+	for i := range silences {
+		silence := &silences[i]
+		remaining, err := silenceRemaining(silence, now)
+		if err != nil {
+			logger.Error(err, "invalid schedule, ignoring silence", "silence", silence.Name)
+			continue
+		}
+		if remaining <= 0 {
+			continue
+		}
+		clusterMatch, err := selectorMatches(silence.Spec.ClusterSelector, mc.Labels)
+		if err != nil {
+			logger.Error(err, "invalid cluster selector, ignoring silence", "silence", silence.Name)
+			continue
+		}
+		if clusterMatch {
+			active = append(active, activeFailoverSilence{silence: silence, remaining: remaining})
+		}
+	}
+	return active
+}
+
+// silencing returns the active silence selecting the DRPlacementControl, and its remaining duration. Returns nil if
+// none does. Silences with invalid dr control selectors are logged and ignored.
+func silencing(ctx context.Context, active []activeFailoverSilence, control ramenv1alpha1.DRPlacementControl) (*rdrtriggerv1alpha1.FailoverSilence, time.Duration) {
+	logger := log.FromContext(ctx)
+	for _, a := range active {
+		controlMatch, err := selectorMatches(a.silence.Spec.DRPlacementControlSelector, control.Labels)
+		if err != nil {
+			logger.Error(err, "invalid dr control selector, ignoring silence", "silence", a.silence.Name)
+			continue
+		}
+		if controlMatch {
+			return a.silence, a.remaining
+		}
+	}
+	return nil, 0
+}
+
+// mapSilenceToClusters is used for mapping FailoverSilence events to requests for the ManagedClusters it selects, so
+// failovers resume right away when a silence is deleted
+func (r *DRTriggerController) mapSilenceToClusters(ctx context.Context, obj client.Object) []reconcile.Request {
+	silence, ok := obj.(*rdrtriggerv1alpha1.FailoverSilence)
+	if !ok {
+		return nil
+	}
+	return r.selectedClusters(ctx, silence.Spec.ClusterSelector)
+}
+
+func init() {
+	metrics.Registry.MustRegister(drApplicationFailoverSilencedMetric)
+}
@@ -0,0 +1,169 @@
+// Copyright (c) 2023 Red Hat, Inc.
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	ramenv1alpha1 "github.com/ramendr/ramen/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	rdrtriggerv1alpha1 "regional-dr-trigger-operator/api/v1alpha1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"time"
+)
+
+var _ = Context("Failover Silences", func() {
+	It("should not failover dr controls selected by an active silence", func(ctx SpecContext) {
+		testName := "silence"
+
+		By("Create an unavailable ManagedCluster")
+		mc := createUnavailableCluster(ctx, testName)
+
+		By("Create a DRPlacementControl eligible for a failover")
+		drControl, ns := createDRControl(ctx, testName, mc.Name, map[string]string{"app": testName},
+			ramenv1alpha1.Deployed, drCondition(ramenv1alpha1.ConditionPeerReady, metav1.ConditionTrue))
+
+		By("Create a FailoverSilence selecting the DRPlacementControl for the next hour")
+		end := metav1.NewTime(time.Now().Add(time.Hour))
+		silence := &rdrtriggerv1alpha1.FailoverSilence{
+			ObjectMeta: metav1.ObjectMeta{Name: testName},
+			Spec: rdrtriggerv1alpha1.FailoverSilenceSpec{
+				DRPlacementControlSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": testName}},
+				EndTime:                    &end,
+				Reason:                     "network maintenance",
+			},
+		}
+		Expect(testClient.Create(ctx, silence)).To(Succeed())
+
+		By("Reconcile for the MC")
+		recorder := record.NewFakeRecorder(10)
+		silenceController := &DRTriggerController{Client: testClient, Scheme: drtController.Scheme, Recorder: recorder}
+		res, err := silenceController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Verify the DRPC was not failed-over and requeued for the end of the silence")
+		Expect(drAction(ctx, drControl)()).To(Equal(ramenv1alpha1.ActionRelocate))
+		Expect(recordedEvents(recorder)).To(ContainElement(ContainSubstring(ReasonFailoverSilenced)))
+		Expect(res.RequeueAfter).To(BeNumerically(">", 58*time.Minute))
+
+		By("Delete the FailoverSilence and reconcile for the MC")
+		Expect(testClient.Delete(ctx, silence)).To(Succeed())
+		_, err = silenceController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Verify the DRPC was failed-over")
+		Expect(drAction(ctx, drControl)()).To(Equal(ramenv1alpha1.ActionFailover))
+
+		By("Cleanups")
+		Expect(testClient.Delete(ctx, drControl)).To(Succeed())
+		Expect(testClient.Delete(ctx, ns)).To(Succeed())
+		Expect(testClient.Delete(ctx, mc)).To(Succeed())
+	})
+
+	It("should evaluate recurring silences using their schedule", func() {
+		// saturday
+		now := time.Date(2025, time.October, 18, 3, 30, 0, 0, time.UTC)
+		silence := &rdrtriggerv1alpha1.FailoverSilence{Spec: rdrtriggerv1alpha1.FailoverSilenceSpec{
+			Schedule: "0 2 * * 6",
+			Duration: &metav1.Duration{Duration: 4 * time.Hour},
+			Reason:   "weekly upgrades",
+		}}
+
+		remaining, err := silenceRemaining(silence, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remaining).To(Equal(150 * time.Minute))
+
+		remaining, err = silenceRemaining(silence, now.Add(3*time.Hour))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remaining).To(BeZero())
+
+		remaining, err = silenceRemaining(silence, now.AddDate(0, 0, 1))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remaining).To(BeZero())
+
+		silence.Spec.Schedule = "0 2 * * 8"
+		_, err = silenceRemaining(silence, now)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should fire cron schedules at the times of their ranges, steps, and lists", func() {
+		// saturday
+		at := func(day, hour, minute int) time.Time {
+			return time.Date(2025, time.October, day, hour, minute, 0, 0, time.UTC)
+		}
+		for _, tc := range []struct {
+			schedule string
+			at       time.Time
+			fires    bool
+		}{
+			// steps and ranges
+			{"*/15 1-3 * * *", at(18, 2, 45), true},
+			{"*/15 1-3 * * *", at(18, 2, 40), false},
+			{"*/15 1-3 * * *", at(18, 4, 0), false},
+			{"10-40/10 0 * * *", at(18, 0, 30), true},
+			{"10-40/10 0 * * *", at(18, 0, 50), false},
+			// lists, of values and ranges
+			{"5,50-52 22-23 * * *", at(18, 23, 51), true},
+			{"5,50-52 22-23 * * *", at(18, 23, 6), false},
+			{"0 0 * 1-12/2 *", at(18, 0, 0), false},
+			{"0 0 * 2-12/2 *", at(18, 0, 0), true},
+			// sunday as 0, or by name
+			{"0 0 * * 0", at(19, 0, 0), true},
+			{"0 0 * * SUN", at(19, 0, 0), true},
+			{"0 0 * * SUN", at(18, 0, 0), false},
+			// either the day of month or the day of week when both are restricted, the 1st or mondays
+			{"0 0 1 * 1", at(1, 0, 0), true},
+			{"0 0 1 * 1", at(6, 0, 0), true},
+			{"0 0 1 * 1", at(7, 0, 0), false},
+			// a day field stepping by one is unrestricted, like standard cron
+			{"0 0 */1 * 1", at(7, 0, 0), false},
+			{"0 0 */1 * 1", at(6, 0, 0), true},
+			{"0 0 1 * */1", at(7, 0, 0), false},
+			{"0 0 1 * */1", at(1, 0, 0), true},
+		} {
+			schedule, err := parseCronSchedule(tc.schedule)
+			Expect(err).NotTo(HaveOccurred(), tc.schedule)
+			Expect(schedule.Next(tc.at.Add(-time.Second)).Equal(tc.at)).To(Equal(tc.fires), "%s at %s", tc.schedule, tc.at)
+		}
+	})
+
+	It("should reject invalid cron schedules", func() {
+		for _, invalid := range []string{"* * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 7",
+			"5-1 * * * *", "*/0 * * * *", "a * * * *", "1-x * * * *"} {
+			_, err := parseCronSchedule(invalid)
+			Expect(err).To(HaveOccurred(), invalid)
+		}
+	})
+
+	It("should find the last firing of cron schedules", func() {
+		// saturday
+		now := time.Date(2025, time.October, 18, 3, 30, 0, 0, time.UTC)
+		for _, tc := range []struct {
+			schedule string
+			now      time.Time
+			within   time.Duration
+			fired    time.Time
+		}{
+			{"*/15 1-3 * * *", now, time.Hour, now},
+			{"*/15 1-3 * * *", now.Add(-time.Minute), time.Hour, time.Date(2025, time.October, 18, 3, 15, 0, 0, time.UTC)},
+			// past the last firing of the day
+			{"*/15 1-3 * * *", now.Add(3 * time.Hour), 2 * time.Hour, time.Time{}},
+			{"*/15 1-3 * * *", now.Add(3 * time.Hour), 3 * time.Hour, time.Date(2025, time.October, 18, 3, 45, 0, 0, time.UTC)},
+			// the previous day, across the month boundary
+			{"50 23 30 9 *", time.Date(2025, time.October, 1, 0, 10, 0, 0, time.UTC), time.Hour,
+				time.Date(2025, time.September, 30, 23, 50, 0, 0, time.UTC)},
+			{"50 23 30 9 *", time.Date(2025, time.October, 1, 0, 10, 0, 0, time.UTC), 10 * time.Minute, time.Time{}},
+			// days earlier, either the day of month or the day of week matching
+			{"0 2 13 * 6", now, maxSilenceDuration, time.Date(2025, time.October, 18, 2, 0, 0, 0, time.UTC)},
+			{"0 2 13 * 6", now.Add(-2 * time.Hour), maxSilenceDuration, time.Date(2025, time.October, 13, 2, 0, 0, 0, time.UTC)},
+			// never firing
+			{"0 0 30 2 *", now, maxSilenceDuration, time.Time{}},
+		} {
+			schedule, err := parseCronSchedule(tc.schedule)
+			Expect(err).NotTo(HaveOccurred(), tc.schedule)
+			Expect(lastFiredWithin(schedule, tc.now, tc.within)).To(Equal(tc.fired), "%s at %s", tc.schedule, tc.now)
+		}
+	})
+})
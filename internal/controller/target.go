@@ -0,0 +1,58 @@
+// Copyright (c) 2023 Red Hat, Inc.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// getTargetCluster is used for fetching the failover target ManagedCluster, returns nil if it does not exist.
+// ManagedClusters are fetched once per reconcile using the targets map.
+func (r *DRTriggerController) getTargetCluster(ctx context.Context, name string, targets map[string]*clusterv1.ManagedCluster) (*clusterv1.ManagedCluster, error) {
+	if target, found := targets[name]; found {
+		return target, nil
+	}
+
+	target := &clusterv1.ManagedCluster{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Name: name}, target); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return nil, err
+		}
+		target = nil
+	}
+	targets[name] = target
+	return target, nil
+}
+
+// targetUnavailableReason returns the reason the failover target ManagedCluster is not healthy enough for receiving
+// a failover, or an empty string if it is joined, accepted by the hub, and available.
+func targetUnavailableReason(name string, target *clusterv1.ManagedCluster) string {
+	if target == nil {
+		return fmt.Sprintf("failover cluster %s not found", name)
+	}
+	if !meta.IsStatusConditionTrue(target.Status.Conditions, clusterv1.ManagedClusterConditionJoined) {
+		return fmt.Sprintf("failover cluster %s is not joined", name)
+	}
+	if !target.Spec.HubAcceptsClient {
+		return fmt.Sprintf("failover cluster %s is not accepted by the hub", name)
+	}
+	if !meta.IsStatusConditionTrue(target.Status.Conditions, clusterv1.ManagedClusterConditionAvailable) {
+		return fmt.Sprintf("failover cluster %s is not available", name)
+	}
+	return ""
+}
+
+// isTargetDown returns true if the failover target ManagedCluster is joined and accepted by the hub, but is not
+// available. Together with the unavailable preferred cluster, the DRPolicy has both of its peers down.
+func isTargetDown(target *clusterv1.ManagedCluster) bool {
+	return target != nil &&
+		meta.IsStatusConditionTrue(target.Status.Conditions, clusterv1.ManagedClusterConditionJoined) &&
+		target.Spec.HubAcceptsClient &&
+		!meta.IsStatusConditionTrue(target.Status.Conditions, clusterv1.ManagedClusterConditionAvailable)
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2023 Red Hat, Inc.
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	ramenv1alpha1 "github.com/ramendr/ramen/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Context("Failover Target", func() {
+	It("should not failover dr controls to an unavailable, joined, failover cluster", func(ctx SpecContext) {
+		testName := "target-unavailable"
+
+		By("Create an unavailable ManagedCluster")
+		mc := createUnavailableCluster(ctx, testName)
+
+		By("Create a DRPlacementControl eligible for a failover")
+		drControl, ns := createDRControl(ctx, testName, mc.Name, nil, ramenv1alpha1.Deployed,
+			drCondition(ramenv1alpha1.ConditionPeerReady, metav1.ConditionTrue))
+
+		By("Update the joined peer MC as unavailable")
+		peer := &clusterv1.ManagedCluster{}
+		Expect(testClient.Get(ctx, client.ObjectKey{Name: testName + "-peer"}, peer)).To(Succeed())
+		meta.SetStatusCondition(&peer.Status.Conditions, metav1.Condition{
+			Type:   clusterv1.ManagedClusterConditionAvailable,
+			Status: metav1.ConditionUnknown,
+			Reason: "MC_Lease_Expired",
+		})
+		Expect(testClient.Status().Update(ctx, peer)).To(Succeed())
+
+		By("Reconcile for the MC with a recording controller")
+		recorder := record.NewFakeRecorder(10)
+		recordingController := &DRTriggerController{Client: testClient, Scheme: drtController.Scheme, Recorder: recorder}
+		_, err := recordingController.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(mc)})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Verify the DRPC was not failed-over, reporting both the dual failure and the unavailable target")
+		Expect(drAction(ctx, drControl)()).To(Equal(ramenv1alpha1.ActionRelocate))
+		events := recordedEvents(recorder)
+		Expect(events).To(ContainElement(ContainSubstring(ReasonDualFailure)))
+		Expect(events).To(ContainElement(ContainSubstring(ReasonFailoverSkippedTargetUnavailable)))
+		Expect(testutil.ToFloat64(drApplicationFailoverTargetUnavailableMetric.WithLabelValues(
+			mc.Name, drControl.Name, drControl.Namespace, peer.Name))).To(Equal(float64(1)))
+
+		By("Cleanups")
+		Expect(testClient.Delete(ctx, drControl)).To(Succeed())
+		Expect(testClient.Delete(ctx, ns)).To(Succeed())
+		Expect(testClient.Delete(ctx, mc)).To(Succeed())
+	})
+})
@@ -0,0 +1,183 @@
+// Copyright (c) 2023 Red Hat, Inc.
+
+package controller
+
+import (
+	"context"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	ramenv1alpha1 "github.com/ramendr/ramen/api/v1alpha1"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"time"
+)
+
+// createUnavailableCluster is a utility function creating a joined and accepted ManagedCluster, reported as not
+// available
+func createUnavailableCluster(ctx context.Context, name string) *clusterv1.ManagedCluster {
+	return createUnavailableClusterSince(ctx, name, time.Now())
+}
+
+// createUnavailableClusterSince is a utility function creating a joined and accepted ManagedCluster, reported as not
+// available since the given time
+func createUnavailableClusterSince(ctx context.Context, name string, since time.Time) *clusterv1.ManagedCluster {
+	mc := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       clusterv1.ManagedClusterSpec{HubAcceptsClient: true},
+	}
+	Expect(testClient.Create(ctx, mc)).To(Succeed())
+
+	mc.Status = clusterv1.ManagedClusterStatus{Conditions: []metav1.Condition{
+		{
+			Type:               clusterv1.ManagedClusterConditionJoined,
+			Status:             metav1.ConditionTrue,
+			Reason:             "MC_Joined",
+			LastTransitionTime: metav1.Now(),
+		},
+		{
+			Type:               clusterv1.ManagedClusterConditionAvailable,
+			Status:             metav1.ConditionFalse,
+			Reason:             "MC_Not_Available",
+			LastTransitionTime: metav1.NewTime(since),
+		},
+	}}
+	Expect(testClient.Status().Update(ctx, mc)).To(Succeed())
+	return mc
+}
+
+// createAvailableCluster is a utility function creating a joined, accepted, and available ManagedCluster, deleted when
+// the spec ends
+func createAvailableCluster(ctx context.Context, name string) *clusterv1.ManagedCluster {
+	mc := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       clusterv1.ManagedClusterSpec{HubAcceptsClient: true},
+	}
+	Expect(testClient.Create(ctx, mc)).To(Succeed())
+	DeferCleanup(func(ctx SpecContext) {
+		Expect(testClient.Delete(ctx, mc)).To(Succeed())
+	})
+
+	mc.Status = clusterv1.ManagedClusterStatus{Conditions: []metav1.Condition{
+		{
+			Type:               clusterv1.ManagedClusterConditionJoined,
+			Status:             metav1.ConditionTrue,
+			Reason:             "MC_Joined",
+			LastTransitionTime: metav1.Now(),
+		},
+		{
+			Type:               clusterv1.ManagedClusterConditionAvailable,
+			Status:             metav1.ConditionTrue,
+			Reason:             "MC_Available",
+			LastTransitionTime: metav1.Now(),
+		},
+	}}
+	Expect(testClient.Status().Update(ctx, mc)).To(Succeed())
+	return mc
+}
+
+// createDRPolicy is a utility function creating a validated DRPolicy for the clusters, deleted when the spec ends
+func createDRPolicy(ctx context.Context, name string, clusters ...string) *ramenv1alpha1.DRPolicy {
+	drPolicy := &ramenv1alpha1.DRPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: name + "-drpolicy"},
+		Spec:       ramenv1alpha1.DRPolicySpec{DRClusters: clusters, SchedulingInterval: "5m"},
+	}
+	Expect(testClient.Create(ctx, drPolicy)).To(Succeed())
+	DeferCleanup(func(ctx SpecContext) {
+		Expect(testClient.Delete(ctx, drPolicy)).To(Succeed())
+	})
+
+	drPolicy.Status.Conditions = []metav1.Condition{{
+		Type:               ramenv1alpha1.DRPolicyValidated,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Succeeded",
+		LastTransitionTime: metav1.Now(),
+	}}
+	Expect(testClient.Status().Update(ctx, drPolicy)).To(Succeed())
+	return drPolicy
+}
+
+// createDRControl is a utility function creating a Namespace and a DRPlacementControl in it. The DRPlacementControl
+// prefers the named cluster, its action is Relocate, and its status is set with the phase and conditions. It uses a
+// validated DRPolicy peering the named cluster with an available name-peer cluster.
+func createDRControl(ctx context.Context, name, cluster string, labels map[string]string, phase ramenv1alpha1.DRState, conditions ...metav1.Condition) (*ramenv1alpha1.DRPlacementControl, *corev1.Namespace) {
+	peer := createAvailableCluster(ctx, name+"-peer")
+	drPolicy := createDRPolicy(ctx, name, cluster, peer.Name)
+
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name + "-ns"}}
+	Expect(testClient.Create(ctx, ns)).To(Succeed())
+
+	drControl := &ramenv1alpha1.DRPlacementControl{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name + "-dr",
+			Namespace: ns.Name,
+			Labels:    labels,
+		},
+		Spec: ramenv1alpha1.DRPlacementControlSpec{
+			Action:      ramenv1alpha1.ActionRelocate,
+			DRPolicyRef: corev1.ObjectReference{Name: drPolicy.Name},
+		},
+	}
+	Expect(testClient.Create(ctx, drControl)).To(Succeed())
+
+	drControl.Status = ramenv1alpha1.DRPlacementControlStatus{
+		PreferredDecision: ramenv1alpha1.PlacementDecision{ClusterName: cluster},
+		Phase:             phase,
+		Conditions:        conditions,
+	}
+	Expect(testClient.Status().Update(ctx, drControl)).To(Succeed())
+	return drControl, ns
+}
+
+// drCondition is a utility function creating a DRPlacementControl condition of the given type and status
+func drCondition(conditionType string, status metav1.ConditionStatus) metav1.Condition {
+	return metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		Reason:             "DR_Condition",
+		LastTransitionTime: metav1.Now(),
+	}
+}
+
+// drAction is a utility function returning a function fetching the current action of a DRPlacementControl, used
+// with Eventually and Consistently
+func drAction(ctx context.Context, drControl *ramenv1alpha1.DRPlacementControl) func() (ramenv1alpha1.DRAction, error) {
+	return func() (ramenv1alpha1.DRAction, error) {
+		drControlUpdate := &ramenv1alpha1.DRPlacementControl{}
+		if err := testClient.Get(ctx, client.ObjectKeyFromObject(drControl), drControlUpdate); err != nil {
+			return "", err
+		}
+		return drControlUpdate.Spec.Action, nil
+	}
+}
+
+// recordedEvents is a utility function draining all events recorded so far by a FakeRecorder
+func recordedEvents(recorder *record.FakeRecorder) []string {
+	var events []string
+	for {
+		select {
+		case e := <-recorder.Events:
+			events = append(events, e)
+		default:
+			return events
+		}
+	}
+}
+
+// createClusterLease is a utility function creating the ManagedCluster namespace with its lease renewed at the given
+// time
+func createClusterLease(ctx context.Context, cluster string, renewedAt time.Time) (*corev1.Namespace, *coordinationv1.Lease) {
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: cluster}}
+	Expect(testClient.Create(ctx, ns)).To(Succeed())
+
+	renewTime := metav1.NewMicroTime(renewedAt)
+	lease := &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{Namespace: cluster, Name: DefaultClusterLeaseName},
+		Spec:       coordinationv1.LeaseSpec{RenewTime: &renewTime},
+	}
+	Expect(testClient.Create(ctx, lease)).To(Succeed())
+	return ns, lease
+}
@@ -7,9 +7,14 @@ import (
 	"fmt"
 	ramenv1alpha1 "github.com/ramendr/ramen/api/v1alpha1"
 	"github.com/spf13/cobra"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	rdrtriggerv1alpha1 "regional-dr-trigger-operator/api/v1alpha1"
 	"regional-dr-trigger-operator/internal/controller"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
@@ -19,8 +24,13 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"time"
 )
 
+// Version is the operator version, set at build time using
+// -ldflags "-X regional-dr-trigger-operator/internal/operator.Version=<version>"
+var Version = "devel"
+
 // DRTriggerOperator is the receiver for running the operator and binding the options
 type DRTriggerOperator struct {
 	Options *DRTriggerOperatorOptions
@@ -28,12 +38,38 @@ type DRTriggerOperator struct {
 
 // DRTriggerOperatorOptions is used for encapsulating the operator options
 type DRTriggerOperatorOptions struct {
-	MetricAddr     string
-	LeaderElection bool
-	ProbeAddr      string
-	Debug          bool
-	MetricsSecure  bool
-	EnableHttp2    bool
+	MetricAddr             string
+	LeaderElection         bool
+	ProbeAddr              string
+	Debug                  bool
+	MetricsSecure          bool
+	EnableHttp2            bool
+	DryRun                 bool
+	MaxFailovers           int
+	MaxFailoversPerCluster int
+	FailoverLimitWindow    time.Duration
+	PriorityReleasePhase   string
+	OptInOnly              bool
+	RequeueInterval        time.Duration
+	BreakerThreshold       float64
+	BreakerWindow          time.Duration
+	Namespace              string
+	FlapThreshold          int
+	FlapWindow             time.Duration
+	FailoverCooldown       time.Duration
+	FailbackHoldPeriod     time.Duration
+	BreakGlassMaxDuration  time.Duration
+	ApprovalMaxDuration    time.Duration
+	LeaseDetection         string
+	LeaseName              string
+	LeaseStaleAfter        time.Duration
+	OutageAddons           []string
+	PrometheusURL          string
+	PrometheusQueries      []string
+	PrometheusTokenFile    string
+	PrometheusInterval     time.Duration
+	PrometheusTimeout      time.Duration
+	PrometheusCAFile       string
 }
 
 // NewDRTriggerOperator is a factory function for creating a regional dr trigger operator instance
@@ -48,6 +84,53 @@ func (c *DRTriggerOperator) Run(cmd *cobra.Command, args []string) error {
 	ctrl.SetLogger(zap.New(zap.UseDevMode(c.Options.Debug)))
 	logger := ctrl.Log.WithName("rdrtrigger-operator")
 
+	// verify the priority release phase, only failover phases are accepted
+	releasePhase := ramenv1alpha1.DRState(c.Options.PriorityReleasePhase)
+	if releasePhase != ramenv1alpha1.FailingOver && releasePhase != ramenv1alpha1.FailedOver {
+		err := fmt.Errorf("unsupported priority release phase %q, expected %s or %s",
+			releasePhase, ramenv1alpha1.FailingOver, ramenv1alpha1.FailedOver)
+		logger.Error(err, "failed verifying options")
+		return err
+	}
+
+	// verify the circuit breaker threshold, a fraction of the clusters
+	if c.Options.BreakerThreshold < 0 || c.Options.BreakerThreshold >= 1 {
+		err := fmt.Errorf("unsupported circuit breaker threshold %v, expected a fraction between 0 and 1",
+			c.Options.BreakerThreshold)
+		logger.Error(err, "failed verifying options")
+		return err
+	}
+
+	// verify the circuit breaker has the operator namespace, saving its state and checked for resets
+	if c.Options.BreakerThreshold > 0 && c.Options.Namespace == "" {
+		err := fmt.Errorf("circuit breaker threshold %v set without the operator namespace", c.Options.BreakerThreshold)
+		logger.Error(err, "failed verifying options")
+		return err
+	}
+
+	// verify the lease detection mode
+	leaseMode := controller.LeaseMode(c.Options.LeaseDetection)
+	if leaseMode != "" && leaseMode != controller.LeaseModeEarly && leaseMode != controller.LeaseModeConfirm {
+		err := fmt.Errorf("unsupported lease detection mode %q, expected %s or %s",
+			leaseMode, controller.LeaseModeEarly, controller.LeaseModeConfirm)
+		logger.Error(err, "failed verifying options")
+		return err
+	}
+
+	// verify the prometheus signal has queries
+	if c.Options.PrometheusURL != "" && len(c.Options.PrometheusQueries) == 0 {
+		err := fmt.Errorf("prometheus url %s set without queries", c.Options.PrometheusURL)
+		logger.Error(err, "failed verifying options")
+		return err
+	}
+
+	// create the prometheus client, verifying the ca file
+	prometheusClient, err := controller.NewPrometheusClient(c.Options.PrometheusCAFile, c.Options.PrometheusTimeout)
+	if err != nil {
+		logger.Error(err, "failed verifying options")
+		return err
+	}
+
 	// create the scheme and install the required types
 	scheme := runtime.NewScheme()
 	if err := installTypes(scheme); err != nil {
@@ -89,6 +172,14 @@ func (c *DRTriggerOperator) Run(cmd *cobra.Command, args []string) error {
 		HealthProbeBindAddress: c.Options.ProbeAddr,
 		Cache: cache.Options{ByObject: map[client.Object]cache.ByObject{
 			&ramenv1alpha1.DRPlacementControl{}: {Label: labels.Everything()},
+			// only leases of managed cluster namespaces are used, node heartbeats and control plane leader elections
+			// renew every few seconds and are not cached
+			&coordinationv1.Lease{}: {Field: fields.ParseSelectorOrDie(
+				"metadata.namespace!=kube-node-lease,metadata.namespace!=kube-system")},
+		}},
+		// failover records are counted for the failover limits, reading them live avoids missing fresh records
+		Client: client.Options{Cache: &client.CacheOptions{
+			DisableFor: []client.Object{&rdrtriggerv1alpha1.FailoverRecord{}},
 		}},
 	})
 	if err != nil {
@@ -96,8 +187,69 @@ func (c *DRTriggerOperator) Run(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	// set up the failover record controller
+	recordController := &controller.FailoverRecordController{
+		Client: mgr.GetClient(),
+		Scheme: scheme,
+	}
+	if err = recordController.SetupWithManager(ctx, mgr); err != nil {
+		logger.Error(err, "failed setting up the failover record controller")
+		return err
+	}
+
+	// set up the circuit breaker, pausing automatic failovers on a mass outage
+	var breaker *controller.CircuitBreaker
+	if c.Options.BreakerThreshold > 0 {
+		breaker = &controller.CircuitBreaker{
+			Threshold: c.Options.BreakerThreshold,
+			Window:    c.Options.BreakerWindow,
+			Namespace: c.Options.Namespace,
+		}
+	}
+
 	// set up the controller
-	controller := &controller.DRTriggerController{Client: mgr.GetClient(), Scheme: scheme}
+	controller := &controller.DRTriggerController{
+		Client:   mgr.GetClient(),
+		Scheme:   scheme,
+		Recorder: mgr.GetEventRecorderFor("regional-dr-trigger-controller"),
+		DryRun:   c.Options.DryRun,
+		Version:  Version,
+		Limits: controller.FailoverLimits{
+			Global:           c.Options.MaxFailovers,
+			PerTargetCluster: c.Options.MaxFailoversPerCluster,
+			Window:           c.Options.FailoverLimitWindow,
+		},
+		PriorityReleasePhase: releasePhase,
+		OptInOnly:            c.Options.OptInOnly,
+		RequeueInterval:      c.Options.RequeueInterval,
+		Breaker:              breaker,
+		Flapping: controller.FlapDetection{
+			Threshold: c.Options.FlapThreshold,
+			Window:    c.Options.FlapWindow,
+		},
+		Cooldown:              c.Options.FailoverCooldown,
+		FailbackHoldPeriod:    c.Options.FailbackHoldPeriod,
+		BreakGlassMaxDuration: c.Options.BreakGlassMaxDuration,
+		ApprovalMaxDuration:   c.Options.ApprovalMaxDuration,
+		Namespace:             c.Options.Namespace,
+		LeaseDetection: controller.LeaseDetection{
+			Mode:       leaseMode,
+			LeaseName:  c.Options.LeaseName,
+			StaleAfter: c.Options.LeaseStaleAfter,
+		},
+		Outages: controller.OutageClassification{
+			Addons:     c.Options.OutageAddons,
+			StaleAfter: c.Options.LeaseStaleAfter,
+		},
+		Prometheus: controller.PrometheusSignal{
+			URL:             c.Options.PrometheusURL,
+			Queries:         c.Options.PrometheusQueries,
+			BearerTokenFile: c.Options.PrometheusTokenFile,
+			Interval:        c.Options.PrometheusInterval,
+			Timeout:         c.Options.PrometheusTimeout,
+			Client:          prometheusClient,
+		},
+	}
 	if err = controller.SetupWithManager(ctx, mgr); err != nil {
 		logger.Error(err, "failed setting up the controller")
 		return err
@@ -112,6 +264,12 @@ func (c *DRTriggerOperator) Run(cmd *cobra.Command, args []string) error {
 		logger.Error(err, "failed setting up ready check")
 		return err
 	}
+	if breaker != nil {
+		if err = mgr.AddReadyzCheck("circuit-breaker", breaker.Check); err != nil {
+			logger.Error(err, "failed setting up circuit breaker ready check")
+			return err
+		}
+	}
 
 	logger.Info("stating manager")
 	return mgr.Start(ctx)
@@ -119,13 +277,29 @@ func (c *DRTriggerOperator) Run(cmd *cobra.Command, args []string) error {
 
 // installTypes is used for installing all the required types with a scheme.
 func installTypes(scheme *runtime.Scheme) error {
+	// required for Namespace
+	if err := corev1.AddToScheme(scheme); err != nil {
+		return fmt.Errorf("failed installing core types into the scheme, %v", err)
+	}
+	// required for Lease
+	if err := coordinationv1.AddToScheme(scheme); err != nil {
+		return fmt.Errorf("failed installing coordination types into the scheme, %v", err)
+	}
 	// required for ManagedCluster
 	if err := clusterv1.Install(scheme); err != nil {
 		return fmt.Errorf("failed installing ocm's types into the scheme, %v", err)
 	}
+	// required for ManagedClusterAddOn
+	if err := addonv1alpha1.Install(scheme); err != nil {
+		return fmt.Errorf("failed installing ocm's addon types into the scheme, %v", err)
+	}
 	// required for DRPlacementControl
 	if err := ramenv1alpha1.AddToScheme(scheme); err != nil {
 		return fmt.Errorf("failed installing ramen's types into the scheme, %v", err)
 	}
+	// required for DRTriggerPolicy and FailoverRecord
+	if err := rdrtriggerv1alpha1.AddToScheme(scheme); err != nil {
+		return fmt.Errorf("failed installing the operator's types into the scheme, %v", err)
+	}
 	return nil
 }